@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
+	"github.com/ninesl/dice-will-roll/render"
+)
+
+// A cave is picked from the Mine before a run. every level of the run digs one of its layers, top to bottom
+type Cave struct {
+	Name   string
+	Layers []CaveLayer
+}
+
+// one depth of a cave
+type CaveLayer struct {
+	Name  string
+	Rocks int         // rocks to clear the layer
+	Color render.Vec3 // base color of the layer's rocks
+	Rules []CaveRule  // only subscribed while the layer is being dug
+}
+
+// a modifier a layer puts on its level. subscribed to the run's events at events.RULE_ORDER, Name is the source
+type CaveRule struct {
+	Name    string
+	Event   events.Event
+	Handler events.Handler
+}
+
+var ErrUnknownCave = errors.New("unknown cave")
+
+// every cave of the Mine, each starts deeper than the last
+var CAVES = []Cave{
+	{
+		Name: "Shist",
+		Layers: []CaveLayer{
+			{Name: "Topsoil", Rocks: 1000, Color: render.Brown},
+			{Name: "Mica", Rocks: 2000, Color: render.Grey},
+			{Name: "Garnet Vein", Rocks: 4000, Color: render.RainbowColors[render.Red],
+				Rules: []CaveRule{handMultRule("Garnet Vein", dice.ONE_PAIR, 2)}},
+			{Name: "Bedrock", Rocks: 8000, Color: render.KageColor(80, 80, 90),
+				Rules: []CaveRule{rollsRule("Bedrock", -1)}},
+		},
+	},
+	{
+		Name: "Slate",
+		Layers: []CaveLayer{
+			{Name: "Shale", Rocks: 2500, Color: render.KageColor(110, 110, 120),
+				Rules: []CaveRule{handMultRule("Shale", dice.FULL_HOUSE, 2)}},
+			{Name: "Slate", Rocks: 5000, Color: render.KageColor(70, 80, 95)},
+			{Name: "Flooded", Rocks: 10000, Color: render.RainbowColors[render.Blue],
+				Rules: []CaveRule{rollsRule("Flooded", 1)}},
+			{Name: "Phyllite", Rocks: 20000, Color: render.KageColor(90, 120, 100),
+				Rules: []CaveRule{handMultRule("Phyllite", dice.HIGH_DIE, 0.5)}},
+		},
+	},
+	{
+		Name: "Gneiss",
+		Layers: []CaveLayer{
+			{Name: "Banded", Rocks: 5000, Color: render.KageColor(160, 140, 120)},
+			{Name: "Feldspar", Rocks: 10000, Color: render.RainbowColors[render.Orange],
+				Rules: []CaveRule{handMultRule("Feldspar", dice.STRAIGHT_SMALL, 2)}},
+			{Name: "Quartz", Rocks: 20000, Color: render.KageColor(220, 220, 230),
+				Rules: []CaveRule{rollsRule("Quartz", -1)}},
+			{Name: "Migmatite", Rocks: 40000, Color: render.KageColor(120, 60, 60)},
+		},
+	},
+	{
+		Name: "Marble",
+		Layers: []CaveLayer{
+			{Name: "Limestone", Rocks: 10000, Color: render.KageColor(200, 195, 170)},
+			{Name: "Calcite", Rocks: 20000, Color: render.RainbowColors[render.Yellow],
+				Rules: []CaveRule{handMultRule("Calcite", dice.TWO_PAIR, 2)}},
+			{Name: "Veined", Rocks: 40000, Color: render.RainbowColors[render.Green]},
+			{Name: "Abyss", Rocks: 60000, Color: render.RainbowColors[render.Indigo],
+				Rules: []CaveRule{rollsRule("Abyss", -1), handMultRule("Abyss", dice.HIGH_DIE, 0.5)}},
+		},
+	},
+}
+
+// the cave called name, any case
+func SelectCave(name string) (Cave, error) {
+	names := make([]string, len(CAVES))
+	for i, cave := range CAVES {
+		if strings.EqualFold(cave.Name, name) {
+			return cave, nil
+		}
+		names[i] = cave.Name
+	}
+	return Cave{}, fmt.Errorf("%w %q, want one of %s", ErrUnknownCave, name, strings.Join(names, ", "))
+}
+
+// the levels of a run through the cave. when startRocks > 0 every layer is scaled so the first has startRocks
+func (c Cave) Levels(startRocks int) []LevelOptions {
+	scale := 1.0
+	if startRocks > 0 && len(c.Layers) > 0 {
+		scale = float64(startRocks) / float64(c.Layers[0].Rocks)
+	}
+
+	levels := make([]LevelOptions, len(c.Layers))
+	for i, layer := range c.Layers {
+		levels[i] = LevelOptions{
+			// rock ids are uint16
+			Rocks: min(max(int(float64(layer.Rocks)*scale), 1), math.MaxUint16),
+			Hands: RUN_HANDS,
+			Rolls: RUN_ROLLS,
+			Index: i,
+			Name:  layer.Name,
+			Color: layer.Color,
+			Rules: layer.Rules,
+		}
+	}
+	return levels
+}
+
+// multiplies hand's Mult by mult
+func handMultRule(name string, hand dice.HandRank, mult float32) CaveRule {
+	return CaveRule{
+		Name:  name,
+		Event: events.AfterHandScored,
+		Handler: func(ctx *events.Context) {
+			if ctx.Hand == hand {
+				ctx.MultScore(mult)
+			}
+		},
+	}
+}
+
+// adds rolls to the first hand of the layer
+func rollsRule(name string, rolls int) CaveRule {
+	return CaveRule{
+		Name:  name,
+		Event: events.OnLevelStarted,
+		Handler: func(ctx *events.Context) {
+			ctx.AddRolls(rolls)
+		},
+	}
+}
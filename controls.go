@@ -6,6 +6,7 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/ninesl/dice-will-roll/events"
 	"github.com/ninesl/dice-will-roll/render"
 )
 
@@ -43,7 +44,7 @@ func (g *Game) Controls() Action {
 
 // assigns dice that are in the hand within ActiveLevel to SCORING
 func (g *Game) SetDiceToScore() {
-	g.ActiveLevel.ScoreHand = g.ActiveLevel.Hand
+	g.ActiveLevel.BeginScoring(g.ActiveLevel.Hand, g.ActiveLevel.ScoringHand)
 
 	for i := 0; i < len(g.ActiveLevel.ScoringHand); i++ {
 		d := g.ActiveLevel.ScoringHand[i]
@@ -71,7 +72,7 @@ func (g *Game) SetDiceToScore() {
 			die.Direction = render.DirectionArr[render.DOWN]
 			die.ZRotation = rand.Float32()
 			// Roll the die face value
-			die.Die.Roll()
+			die.Die.Roll(die.rolls)
 			// deselect rocks, no color for rocks that aren't in scoring hand
 			g.RocksRenderer.DeselectRocks(die.Identifier)
 		}
@@ -87,6 +88,8 @@ func (g *Game) Press(die *Die) {
 		g.holdCx = g.Mouse.Position.X
 		g.holdCy = g.Mouse.Position.Y
 
+		g.pressedHeld = die.Mode == HELD
+
 		// g.Time = time.Now()
 
 		// where the mouse was clicked
@@ -126,6 +129,9 @@ func (g *Game) Select() {
 
 			g.ResetHoldPoint()
 			die.Mode = ROLLING
+			if g.pressedHeld {
+				g.ActiveLevel.Fire(events.OnRelease, die)
+			}
 
 			g.RocksRenderer.DeselectRocks(die.Identifier)
 
@@ -136,6 +142,9 @@ func (g *Game) Select() {
 
 		g.ResetHoldPoint()
 		die.Mode = HELD
+		if !g.pressedHeld {
+			g.ActiveLevel.Fire(events.OnHold, die)
+		}
 
 		g.RocksRenderer.SelectRocksColor(die.Color, die.Identifier, len(g.Dice), die.ActiveFace().NumPips())
 
@@ -150,6 +159,9 @@ func (g *Game) Select() {
 
 	// let go of die
 	die.Mode = ROLLING
+	if g.pressedHeld {
+		g.ActiveLevel.Fire(events.OnRelease, die)
+	}
 
 	// clamp workaround, needed if no more rolls
 	if !g.cursorWithin(render.ROLLZONE) {
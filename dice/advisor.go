@@ -0,0 +1,70 @@
+package dice
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// SuggestHold finds the dice to hold that give the best expected score, and that expected score.
+//
+// rollsLeft is how many more times the unheld dice get rolled. mults come from table, nil uses the defaults.
+// every hold is tried, the rolls after the first follow RerollOdds: keep the best hand, roll the rest.
+// ties go to the hold with the fewest dice
+//
+//	hold, expected := SuggestHold(dice, level.RollsLeft, run.Hands)
+//	hold // [0, 3, 4] indexes into dice
+func SuggestHold(dice []Die, rollsLeft int, table *HandTable) ([]int, float64) {
+	if len(dice) == 0 {
+		return nil, 0
+	}
+
+	if rollsLeft <= 0 { // nothing left to roll, hold the best hand
+		result := ScoreBreakdown(dice, table)
+		hold := make([]int, 0, len(result.Dice))
+		for _, die := range result.Dice {
+			hold = append(hold, die.Index)
+		}
+		return hold, float64(result.Total)
+	}
+
+	solver := newOddsSolver(nil, dice, newOddsMemo(table))
+	showing := make([]uint8, len(dice))
+	for i := range dice {
+		showing[i] = solver.faceID(dice[i].ActiveFace())
+	}
+
+	var (
+		bestHold  []int
+		bestScore = -1.0
+		kept      = make([]keptDie, 0, len(dice))
+		hold      = make([]int, 0, len(dice))
+	)
+
+	// every subset of dice, smaller holds first so they win ties
+	masks := make([]int, 1<<len(dice))
+	for mask := range masks {
+		masks[mask] = mask
+	}
+	slices.SortStableFunc(masks, func(a, b int) int {
+		return bits.OnesCount(uint(a)) - bits.OnesCount(uint(b))
+	})
+
+	for _, mask := range masks {
+		kept = kept[:0]
+		hold = hold[:0]
+		for i := range dice {
+			if mask&(1<<i) != 0 {
+				kept = append(kept, keptDie{kind: uint8(solver.dieKinds[i]), id: showing[i]})
+				hold = append(hold, i)
+			}
+		}
+		sortKept(kept)
+
+		if score := solver.solve(kept, rollsLeft).score; score > bestScore {
+			bestScore = score
+			bestHold = slices.Clone(hold)
+		}
+	}
+
+	return bestHold, bestScore
+}
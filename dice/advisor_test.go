@@ -0,0 +1,113 @@
+package dice
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// average Total of holding mask and rolling everything else once, the slow way
+func bruteForceExpected(dice []Die, mask int, table *HandTable) float64 {
+	hand := slices.Clone(dice)
+	var rolling []int
+	for i := range hand {
+		if mask&(1<<i) == 0 {
+			rolling = append(rolling, i)
+		}
+	}
+
+	var expected float64
+	var roll func(r int, chance float64)
+	roll = func(r int, chance float64) {
+		if r == len(rolling) {
+			expected += chance * float64(ScoreBreakdown(hand, table).Total)
+			return
+		}
+		die := &hand[rolling[r]]
+		for face := range die.faces {
+			die.activeFace = face
+			roll(r+1, chance/float64(len(die.faces)))
+		}
+	}
+	roll(0, 1)
+	return expected
+}
+
+func TestSuggestHoldMatchesBruteForce(t *testing.T) {
+	table := NewHandTable()
+	table.SetMult(STRAIGHT_SMALL, 30) // makes going for the straight worth it
+
+	tests := []struct {
+		name string
+		dice []Die
+	}{
+		{"pair and singles", generateDiceValues([]int{2, 2, 5, 6}, 6)},
+		{"almost a straight", generateDiceValues([]int{1, 2, 3, 6}, 6)},
+		{"modded faces", []Die{
+			{faces: []Face{NewModFace(ModDOUBLE, ModNONE), MustFace(3), MustFace(4)}},
+			{faces: []Face{MustFace(1), NewModFace(ModWILD)}},
+			dieShowing(MustFace(5)),
+			{faces: []Face{MustFace(5), MustFace(6)}},
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hold, expected := SuggestHold(tc.dice, 1, table)
+
+			var mask int
+			for _, i := range hold {
+				mask |= 1 << i
+			}
+			if want := bruteForceExpected(tc.dice, mask, table); math.Abs(expected-want) > 1e-9 {
+				t.Errorf("SuggestHold() expects %.4f for %v; brute force gives %.4f", expected, hold, want)
+			}
+
+			for other := range 1 << len(tc.dice) {
+				if score := bruteForceExpected(tc.dice, other, table); score > expected+1e-9 {
+					t.Errorf("holding %b expects %.4f, better than the suggested %v at %.4f", other, score, hold, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestHold(t *testing.T) {
+	t.Run("no rolls left holds the best hand", func(t *testing.T) {
+		dice := generateDiceValues([]int{1, 4, 4, 6, 4}, 6)
+		hold, expected := SuggestHold(dice, 0, nil)
+		if !slices.Equal(hold, []int{1, 2, 4}) {
+			t.Errorf("hold = %v; want [1 2 4]", hold)
+		}
+		if int(expected) != ScoreBreakdown(dice, nil).Total {
+			t.Errorf("expected = %.2f; want %d", expected, ScoreBreakdown(dice, nil).Total)
+		}
+	})
+
+	t.Run("nothing beats five of a kind", func(t *testing.T) {
+		dice := generateDiceValues([]int{6, 6, 6, 6, 6}, 6)
+		hold, expected := SuggestHold(dice, 2, nil)
+		if len(hold) != 5 {
+			t.Errorf("hold = %v; want every die", hold)
+		}
+		if int(expected) != ScoreBreakdown(dice, nil).Total {
+			t.Errorf("expected = %.2f; want %d", expected, ScoreBreakdown(dice, nil).Total)
+		}
+	})
+
+	t.Run("more rolls never expect less", func(t *testing.T) {
+		dice := generateDiceValues([]int{3, 3, 1, 5, 6}, 6)
+		_, once := SuggestHold(dice, 1, nil)
+		_, twice := SuggestHold(dice, 2, nil)
+		if twice+1e-9 < once {
+			t.Errorf("2 rolls expects %.4f; less than 1 roll at %.4f", twice, once)
+		}
+	})
+}
+
+func BenchmarkSuggestHoldSevenDice(b *testing.B) {
+	dice := generateDiceValues([]int{1, 2, 2, 4, 5, 6, 6}, 6)
+	for b.Loop() {
+		SuggestHold(dice, 2, nil)
+	}
+}
@@ -2,26 +2,15 @@ package dice
 
 import (
 	"fmt"
-	"log"
-	"math/rand"
+	"math/rand/v2"
+	"slices"
 	"strings"
 )
 
-// A Modifier is the buff (or debuff) that gets applied to
-// all varieties of items throughout DWR.
-//
-// Usage: use the 'enums' available for all valid Modifiers
-//
-// The default Modifier (0) is
-type Modifier uint8
-
-const (
-	ModNONE Modifier = iota // ModNONE is the default modifier. It has no additonal effects by default
-)
-
 type Die struct {
 	activeFace int // activeFace is the face that is 'showing' on the Die
 	faces      []Face
+	weights    []float32 // base roll weight of each face, nil when every face weighs DEFAULT_WEIGHT. see Die.Weight
 }
 
 // A Face is from Die's faces
@@ -30,14 +19,21 @@ type Die struct {
 //
 // Value, when refering to Faces, is the literal number of Pips (dots) on a face
 type Face struct {
-	// mod  Modifier
-	pips []Modifier
+	pips   []Modifier // every pip carries one Modifier, ModNONE by default
+	alts   []int      // other values the face can count as. a "2 or 5" face has 2 pips and alts [5]
+	offset int        // added to the number of pips for the face's value. a d20's 20 face is 2 pips + 18
 }
 
-// Most pips a face can have. Not the highest value
+// Most pips a face can have. Not the highest value, see NewValueFace
 const MAX_PIPS = 9
 
-// TODO:FIXME: will be obnoxious to add sides. maybe an interface for the die that works with all variations of faces?
+// the range a face's base value can be set to, see NewValueFace and Die.SetValue
+const (
+	MIN_FACE_VALUE = -99
+	MAX_FACE_VALUE = 99
+)
+
+// the faces of a d6, also the 6 slots of the die shader. every DieShape is drawn on these, see Die.LocationsPips
 const (
 	FrontFace int = iota
 	LeftFace
@@ -54,40 +50,69 @@ const (
 	// [5] behind 6 pip
 )
 
-// ONLY WORKS FOR 6 SIDES
+// the die face drawn in each shader slot while the active face is showing.
 //
-// is used to populate [6]mat3 for shader uniforms
-// func (d *Die) LocationsPips() [6][9]float32 {
-// 	pipLocations := [6][9]float32{}
-// 	for i := range len(pipLocations) {
-// 		locs := d.faces[i].pipLocations()
-// 		pipLocations[i] = locs
-// 	}
+// a d6 is drawn as is. other shapes put the active face in FrontFace, the faces it can tumble to around it
+// and the face opposite it (or one more neighbor) behind it
+func (d *Die) shaderFaces() [6]int {
+	shape := d.Shape()
+	if shape == D6 {
+		return [6]int{FrontFace, LeftFace, BottomFace, TopFace, RightFace, BehindFace}
+	}
 
-// 	return pipLocations
-// }
+	slots := [6]int{}
+	for i := range slots {
+		slots[i] = -1 // blank
+	}
+	slots[FrontFace] = d.activeFace
+
+	neighbors := shape.Adjacent(d.activeFace)
+	for i, slot := range []int{LeftFace, BottomFace, TopFace, RightFace} {
+		if i < len(neighbors) {
+			slots[slot] = neighbors[i]
+		}
+	}
+
+	behind := -1
+	for face := range d.faces {
+		if face != d.activeFace && shape.UpFace(face) == d.activeFace {
+			behind = face // the face it landed on
+		}
+	}
+	if behind == -1 && len(neighbors) > 4 {
+		behind = neighbors[4]
+	}
+	slots[BehindFace] = behind
+
+	return slots
+}
+
+// the shader slot the active face is drawn in. use with LocationsPips
+func (d *Die) ShaderFace() int {
+	slots := d.shaderFaces()
+	return slices.Index(slots[:], d.activeFace)
+}
 
-// ONLY WORKS FOR 6 SIDES
-//
 // is used to populate [6]mat3 for shader uniforms
 // This now returns a flat []float32, with each 9-float segment representing a mat3 in column-major order.
+//
+// works for every DieShape, see shaderFaces for which face goes in which slot
 func (d *Die) LocationsPips() []float32 {
 	// Each mat3 has 9 floats. 6 faces * 9 floats/face = 54 floats total.
 	flattenedPipLayouts := make([]float32, 6*9)
 	flatIdx := 0
+	shape := d.Shape()
 
-	for faceIndex := 0; faceIndex < 6; faceIndex++ {
+	for _, faceIndex := range d.shaderFaces() {
 		var currentFacePipsRowMajor [9]float32
-		// Ensure we don't go out of bounds if a die somehow has fewer than 6 faces defined,
-		// though NewDie(6) should prevent this.
-		if faceIndex < len(d.faces) {
-			currentFacePipsRowMajor = d.faces[faceIndex].pipLocations() // This is row-major based on iota constants
+		if faceIndex >= 0 {
+			currentFacePipsRowMajor = shape.PipLayout(faceIndex, d.faces[faceIndex].NumPips()) // This is row-major based on iota constants
 		} else {
-			// Fallback for safety: an empty face (all zeros)
+			// a blank slot: an empty face (all zeros)
 			currentFacePipsRowMajor = [9]float32{}
 		}
 
-		// Transpose from row-major (from pipLocations) to column-major for the shader.
+		// Transpose from row-major (from PipLayout) to column-major for the shader.
 		// Visual pip grid: V[row][col]
 		// Your iota constants produce row-major order for currentFacePipsRowMajor:
 		// Index: 0   1   2    3   4   5    6   7   8
@@ -116,11 +141,11 @@ func (d *Die) LocationsPips() []float32 {
 	return flattenedPipLayouts
 }
 
-// gives values from 1-9 for each face
+// gives values from 1-9 for each face, panics on any other value. see MustFace
 func New6SidedDie(values [6]int) Die {
 	faces := []Face{}
 	for _, val := range values {
-		faces = append(faces, NewFace(val))
+		faces = append(faces, MustFace(val))
 	}
 	return Die{
 		faces: faces,
@@ -128,11 +153,14 @@ func New6SidedDie(values [6]int) Die {
 }
 
 // Makes a blank die with each face being one more than the last, starting from 1
+//
+// sides picks the DieShape, NewDie(20) is a d20 with values 1 - 20. faces can't have more than MAX_PIPS pips
+// so bigger dice are drawn starting back at 1 pip after MAX_PIPS, their value keeps going
 func NewDie(sides int) Die {
 	faces := []Face{}
 
 	for i := range sides {
-		faces = append(faces, NewFace(i+1)) // so we dont have 0-5 pips
+		faces = append(faces, MustValueFace(i%MAX_PIPS+1, i+1)) // so we dont have 0-5 pips
 	}
 
 	return Die{
@@ -140,9 +168,21 @@ func NewDie(sides int) Die {
 	}
 }
 
-func NewFace(pips int) Face {
-	if pips < 1 || pips > MAX_PIPS {
-		log.Fatalf("could not make a dieface with %d pips. Must be between 1 - %d", pips, MAX_PIPS)
+// checks a face can have this many pips. ErrNoPips or ErrTooManyPips
+func validPips(pips int) error {
+	if pips < 1 {
+		return fmt.Errorf("%w, not %d", ErrNoPips, pips)
+	}
+	if pips > MAX_PIPS {
+		return fmt.Errorf("%w (%d), not %d", ErrTooManyPips, MAX_PIPS, pips)
+	}
+	return nil
+}
+
+// makes a blank (ModNONE) face with 1 - MAX_PIPS pips
+func NewFace(pips int) (Face, error) {
+	if err := validPips(pips); err != nil {
+		return Face{}, fmt.Errorf("could not make a dieface: %w", err)
 	}
 
 	facePips := []Modifier{}
@@ -152,7 +192,82 @@ func NewFace(pips int) Face {
 
 	return Face{
 		pips: facePips,
+	}, nil
+}
+
+// like NewFace but panics on a bad pip count. for faces that are known to be fine
+//
+//	MustFace(6)
+func MustFace(pips int) Face {
+	face, err := NewFace(pips)
+	if err != nil {
+		panic(err)
 	}
+	return face
+}
+
+// checks a face's base value is in MIN_FACE_VALUE - MAX_FACE_VALUE
+func validValue(value int) error {
+	if value < MIN_FACE_VALUE || value > MAX_FACE_VALUE {
+		return fmt.Errorf("%w, not %d", ErrInvalidValue, value)
+	}
+	return nil
+}
+
+// NewValueFace makes a blank face drawn with pips pips that counts as value
+//
+//	NewValueFace(2, 20) // a d20's 20, drawn with 2 pips
+//	NewValueFace(1, 0)  // a 0
+func NewValueFace(pips int, value int) (Face, error) {
+	face, err := NewFace(pips)
+	if err != nil {
+		return Face{}, err
+	}
+	if err := validValue(value); err != nil {
+		return Face{}, fmt.Errorf("could not make a dieface: %w", err)
+	}
+	face.offset = value - pips
+	return face, nil
+}
+
+// like NewValueFace but panics on a bad pip count or value
+func MustValueFace(pips int, value int) Face {
+	face, err := NewValueFace(pips, value)
+	if err != nil {
+		panic(err)
+	}
+	return face
+}
+
+// NewSplitFace makes a face that counts as its number of pips OR any of alts
+//
+//	NewSplitFace(2, 5)  // "2 or 5"
+//	NewSplitFace(2, 20) // "2 or 20", alts can be any value a face can have
+//
+// the hand evaluator picks whichever value gives the best HandRank
+func NewSplitFace(pips int, alts ...int) (Face, error) {
+	face, err := NewFace(pips)
+	if err != nil {
+		return Face{}, err
+	}
+	for _, alt := range alts {
+		if validValue(alt) != nil {
+			return Face{}, fmt.Errorf("could not make a dieface that counts as %d: %w", alt, ErrInvalidAlt)
+		}
+		if alt != pips && !slices.Contains(face.alts, alt) {
+			face.alts = append(face.alts, alt)
+		}
+	}
+	return face, nil
+}
+
+// like NewSplitFace but panics on a bad pip count or alt
+func MustSplitFace(pips int, alts ...int) Face {
+	face, err := NewSplitFace(pips, alts...)
+	if err != nil {
+		panic(err)
+	}
+	return face
 }
 
 // Returns X blank (ModNONE) dice with 6 faces, each.
@@ -179,8 +294,8 @@ func BlankDiceRange(numDice int, maxValue int) []Die {
 func (d *Die) String() string {
 	var sb strings.Builder
 	for i := range d.faces {
-		sb.WriteString(fmt.Sprintf("side %d - %d pips\n",
-			i, len(d.faces[i].pips)))
+		sb.WriteString(fmt.Sprintf("side %d - %d pips %v\n",
+			i, len(d.faces[i].pips), d.faces[i].pips))
 	}
 	return sb.String()
 }
@@ -192,6 +307,15 @@ func (d *Die) ActiveFace() *Face {
 	return &d.faces[d.activeFace]
 }
 
+// Returns a pointer to face i, 0 - NumFaces()-1. same rules as ActiveFace, use the edits in edit.go to change it
+func (d *Die) Face(i int) *Face {
+	return &d.faces[i]
+}
+
+func (d *Die) NumFaces() int {
+	return len(d.faces)
+}
+
 // Returns the index of the face that is active.
 //
 // Used for which side is visible for uniforms
@@ -199,13 +323,21 @@ func (d *Die) ActiveFaceIndex() int {
 	return d.activeFace
 }
 
-// Set the active face to a random 0-len(faces)
+// Set the active face to a random 0-len(faces), loaded dice favor their heavier faces. see Die.FaceChances
 //
 //	d.ActiveFace() # is called to return the pointer to Face
 //
+// r should be the run's dice stream (rng.Streams.Dice) so a seed always rolls the same faces
+//
 // SHOULD NOT BE USED TO MODIFY THE FACE IT RETURNS! (except in specific cases)
-func (d *Die) Roll() *Face {
-	d.activeFace = rand.Intn(len(d.faces))
+func (d *Die) Roll(r *rand.Rand) *Face {
+	if !d.IsLoaded() {
+		down := r.IntN(len(d.faces)) // the face it lands on
+		d.activeFace = d.Shape().UpFace(down)
+		return d.ActiveFace()
+	}
+
+	d.activeFace = d.pickWeighted(r.Float64())
 	return d.ActiveFace()
 }
 
@@ -218,16 +350,54 @@ func (d *Die) NumPips() int {
 	return sum
 }
 
-// Value is the literal NUMBER of pips on the face and relevant modifiers (to the die, not enviornment) are applied
+// Value is what the face counts as, with the relevant modifiers (to the die, not enviornment) applied.
+// it is what hands, tie breakers and scoring use. how many pips are drawn is NumPips
+//
+// Resolution order:
+//
+//  1. the face's BaseValue
+//  2. + every pip's value modifier (ModHOLLOW)
+//
+// can be above MAX_PIPS, 0 or negative. ModWILD is NOT resolved here, see DetermineHandRank
 func (f *Face) Value() int {
-	value := f.NumPips()
+	value := f.BaseValue()
 
-	// modifier?
+	for _, mod := range f.pips {
+		value += modifierTable[mod].value
+	}
 
 	return value
 }
 
-// NumPips returns len(f.pips)
+// the face's value before any modifier. the number of pips unless it was set apart, see NewValueFace
+func (f *Face) BaseValue() int {
+	return f.NumPips() + f.offset
+}
+
+// Candidates are every value the face could count as when determining a HandRank.
+//
+//	wild faces (ModWILD) -> 1 through MAX_PIPS
+//	split faces ("2 or 5") -> .Value() and each alternate
+//	everything else -> .Value()
+func (f *Face) Candidates() []int {
+	if f.IsWild() {
+		candidates := make([]int, 0, MAX_PIPS)
+		for value := 1; value <= MAX_PIPS; value++ {
+			candidates = append(candidates, value)
+		}
+		return candidates
+	}
+
+	candidates := []int{f.Value()}
+	for _, alt := range f.alts {
+		if !slices.Contains(candidates, alt) {
+			candidates = append(candidates, alt)
+		}
+	}
+	return candidates
+}
+
+// NumPips returns len(f.pips), the pips that get drawn. see Value for what the face counts as
 func (f *Face) NumPips() int {
 	return len(f.pips)
 }
@@ -248,101 +418,23 @@ const (
 	bottomRight
 )
 
-// used for shader uniforms
-//
-// TODO: bool might not work long term, we may need another check for gems/mods in pips
-//
-// returns a 9 length true/false array
+// Score is the number that gets added to the total when the player plays a hand
 //
-// each index corresponds to where on the die face the pip is
+// Resolution order:
 //
-//	[false, false, false,
-//	 false, true, false,
-//	 false, false, false] // die face with 1 pip
-func (f *Face) pipLocations() [9]float32 {
-	pipLoc := [9]float32{}
-
-	switch f.NumPips() {
-	case 1:
-		pipLoc[middle] = 1.0
-	case 2:
-		pipLoc[topRight] = 1.0
-		pipLoc[bottomLeft] = 1.0
-	case 3:
-		pipLoc[topLeft] = 1.0
-		pipLoc[middle] = 1.0
-		pipLoc[bottomRight] = 1.0
-	case 4:
-		pipLoc[topLeft] = 1.0
-		pipLoc[topRight] = 1.0
-		pipLoc[bottomRight] = 1.0
-		pipLoc[bottomLeft] = 1.0
-	case 5:
-		pipLoc[topLeft] = 1.0
-		pipLoc[topRight] = 1.0
-		pipLoc[middle] = 1.0
-		pipLoc[bottomRight] = 1.0
-		pipLoc[bottomLeft] = 1.0
-	case 6:
-		pipLoc[topLeft] = 1.0
-		pipLoc[middleLeft] = 1.0
-		pipLoc[bottomLeft] = 1.0
-		pipLoc[topRight] = 1.0
-		pipLoc[middleRight] = 1.0
-		pipLoc[bottomRight] = 1.0
-	case 7:
-		pipLoc[middle] = 1.0
-		pipLoc[topLeft] = 1.0
-		pipLoc[middleLeft] = 1.0
-		pipLoc[bottomLeft] = 1.0
-		pipLoc[topRight] = 1.0
-		pipLoc[middleRight] = 1.0
-		pipLoc[bottomRight] = 1.0
-	case 8:
-		pipLoc[topLeft] = 1.0
-		pipLoc[middleLeft] = 1.0
-		pipLoc[bottomLeft] = 1.0
-		pipLoc[topRight] = 1.0
-		pipLoc[middleRight] = 1.0
-		pipLoc[bottomRight] = 1.0
-		pipLoc[topMiddle] = 1.0
-		pipLoc[bottomMiddle] = 1.0
-	case 9:
-		pipLoc[topLeft] = 1.0
-		pipLoc[topMiddle] = 1.0
-		pipLoc[topRight] = 1.0
-		pipLoc[middleLeft] = 1.0
-		pipLoc[middle] = 1.0
-		pipLoc[middleRight] = 1.0
-		pipLoc[bottomRight] = 1.0
-		pipLoc[bottomMiddle] = 1.0
-		pipLoc[bottomLeft] = 1.0
-	}
-
-	return pipLoc
-}
-
-// Score is the number that gets added to the total when the player plays a hand
+//  1. the face's .Value()
+//  2. + every pip's flat bonus (ModBONUS, ModCRACKED, etc)
+//  3. x every pip's multiplier (ModDOUBLE, ModDULL, etc) in pip order
+//  4. never goes below 0
 func (f *Face) Score() int {
-	/*
-		var (
-			score = 0
-		)
-		// for i := range f.pips {
-		for range f.pips {
-			score += 1
-			// score += f.pips[i] * f.mod
-
-			// TODO: modifier math
-		}
-	*/
-	return f.Value()
+	_, score := f.scoreModifiers()
+	return score
 }
 
 func DiceString(dice []Die) string {
 	s := "["
 	for i := range dice {
-		s = fmt.Sprintf("%s%d,", s, dice[i].ActiveFace().NumPips())
+		s = fmt.Sprintf("%s%d,", s, dice[i].ActiveFace().Value())
 	}
 	return s + "]"
 }
@@ -0,0 +1,118 @@
+package dice
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ninesl/dice-will-roll/rng"
+)
+
+// the same seed always rolls the same sequence of faces
+func TestRollSeeded(t *testing.T) {
+	rollFaces := func(seed uint64) []int {
+		streams := rng.New(seed)
+		dice := BlankDice(7)
+		var faces []int
+		for range 20 {
+			for i := range dice {
+				faces = append(faces, dice[i].Roll(streams.Dice).NumPips())
+			}
+		}
+		return faces
+	}
+
+	first, second := rollFaces(1234), rollFaces(1234)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("roll %d: seed 1234 rolled %d then %d", i, first[i], second[i])
+		}
+	}
+
+	other := rollFaces(4321)
+	for i := range first {
+		if first[i] != other[i] {
+			return
+		}
+	}
+	t.Fatal("seeds 1234 and 4321 rolled the same faces")
+}
+
+// what a face counts as is apart from how many pips it draws
+func TestFaceValues(t *testing.T) {
+	d20 := NewDie(20)
+	for i := range d20.NumFaces() {
+		face := d20.Face(i)
+		if face.Value() != i+1 {
+			t.Errorf("d20 face %d value = %d; want %d", i, face.Value(), i+1)
+		}
+		if face.NumPips() < 1 || face.NumPips() > MAX_PIPS {
+			t.Errorf("d20 face %d draws %d pips", i, face.NumPips())
+		}
+	}
+
+	if _, err := NewValueFace(3, MAX_FACE_VALUE+1); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("NewValueFace(3, %d) error = %v; want %v", MAX_FACE_VALUE+1, err, ErrInvalidValue)
+	}
+	negative, hollow := MustValueFace(2, -3), NewModFace(ModHOLLOW)
+	if got := negative.Value(); got != -3 {
+		t.Errorf("negative face value = %d; want -3", got)
+	}
+	if got := hollow.Value(); got != 0 {
+		t.Errorf("hollow 1 value = %d; want 0", got)
+	}
+
+	// a face drawn with 9 pips that counts as 2 or 5
+	split := Die{faces: []Face{MustSplitFace(9, 5)}}
+	if _, err := split.SetValue(0, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		faces      []Face
+		expected   HandRank
+		wantValues []int
+		wantPips   []int // pips of the found dice, to see which dice won the tie break
+	}{
+		{"pair above MAX_PIPS", []Face{MustValueFace(3, 12), MustValueFace(6, 12), MustFace(6)}, ONE_PAIR, []int{12, 12}, []int{3, 6}},
+		{"snake eyes from value, not pips", []Face{MustValueFace(4, 1), MustFace(1), MustFace(3)}, SNAKE_EYES, []int{1, 1}, []int{1, 4}},
+		{"three pips counting 1 aren't a pair of 3s", []Face{MustValueFace(3, 1), MustFace(3), MustFace(5)}, HIGH_DIE, []int{5}, []int{5}},
+		{"zeros pair up", []Face{MustValueFace(1, 0), NewModFace(ModHOLLOW), MustFace(4)}, ONE_PAIR, []int{0, 0}, []int{1, 1}},
+		{"negatives make a straight", []Face{MustValueFace(1, -1), MustValueFace(1, 0), MustFace(1), MustFace(2)}, STRAIGHT_SMALL, []int{-1, 0, 1, 2}, []int{1, 1, 1, 2}},
+		{"high die below 0", []Face{MustValueFace(1, -5), MustValueFace(2, -2)}, HIGH_DIE, []int{-2}, []int{2}},
+		{"a face with a set value keeps its alts", []Face{split.faces[0], MustFace(5), MustValueFace(1, 5)}, THREE_OF_A_KIND, []int{5, 5, 5}, []int{1, 5, 9}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dice := make([]Die, len(tc.faces))
+			for i, face := range tc.faces {
+				dice[i] = dieShowing(face)
+			}
+
+			got := DetermineHandRank(dice)
+			if got != tc.expected {
+				t.Fatalf("DetermineHandRank() = %s; want %s", got.String(), handRankStringMap[tc.expected])
+			}
+
+			found, values := FindHandRankValues(got, dice, nil)
+			var pips []int
+			for i := range found {
+				pips = append(pips, found[i].ActiveFace().NumPips())
+			}
+			sort.Ints(values)
+			sort.Ints(pips)
+			if !reflect.DeepEqual(values, tc.wantValues) || !reflect.DeepEqual(pips, tc.wantPips) {
+				t.Errorf("found values %v with pips %v; want %v with %v", values, pips, tc.wantValues, tc.wantPips)
+			}
+		})
+	}
+
+	// scoring and the breakdown use the value too
+	d12 := dieShowing(MustValueFace(3, 12))
+	if got := Score([]Die{d12}); got != 12 {
+		t.Errorf("Score() of a 12 drawn with 3 pips = %d; want 12", got)
+	}
+}
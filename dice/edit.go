@@ -0,0 +1,272 @@
+package dice
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Upgrading dice
+//
+// every change to a built die goes through these, they never leave the die in a state
+// a constructor couldn't make. each one returns an Edit that can undo it
+//
+//	edit, err := die.AddPip(2, ModBONUS)
+//	if err != nil {
+//		// *EditError, errors.Is(err, ErrTooManyPips)
+//	}
+//	// show the preview...
+//	die.Undo(edit) // changed their mind
+
+var (
+	ErrNoPips          = errors.New("a face needs at least 1 pip")
+	ErrTooManyPips     = errors.New("a face can't have more than MAX_PIPS pips")
+	ErrInvalidAlt      = errors.New("a face can only count as MIN_FACE_VALUE - MAX_FACE_VALUE")
+	ErrInvalidValue    = errors.New("a face's value has to be MIN_FACE_VALUE - MAX_FACE_VALUE")
+	ErrInvalidFace     = errors.New("no such face")
+	ErrInvalidPip      = errors.New("no such pip")
+	ErrUnknownModifier = errors.New("unknown modifier")
+	ErrStaleEdit       = errors.New("die changed since the edit") // Undo/Redo on a die that doesn't match the Edit
+)
+
+// EditOp is the kind of change an Edit made
+type EditOp uint8
+
+const (
+	EditAddPip EditOp = iota
+	EditRemovePip
+	EditSetPips
+	EditSwapFaces
+	EditCopyFace
+	EditSetModifier
+	EditSetValue
+)
+
+var editOpStringMap = map[EditOp]string{
+	EditAddPip:      "add pip",
+	EditRemovePip:   "remove pip",
+	EditSetPips:     "set pips",
+	EditSwapFaces:   "swap faces",
+	EditCopyFace:    "copy face",
+	EditSetModifier: "set modifier",
+	EditSetValue:    "set value",
+}
+
+func (op EditOp) String() string {
+	if name, ok := editOpStringMap[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("EditOp(%d)", uint8(op))
+}
+
+// EditError is why an edit couldn't be made. Err is one of the Err* above
+type EditError struct {
+	Op   EditOp
+	Face int
+	Err  error
+}
+
+func (e *EditError) Error() string {
+	return fmt.Sprintf("could not %s on face %d: %v", e.Op, e.Face, e.Err)
+}
+
+func (e *EditError) Unwrap() error {
+	return e.Err
+}
+
+// Edit is a change that was made to a die, enough to undo it or show a before/after preview
+type Edit struct {
+	Op     EditOp
+	Faces  []int  // every face that changed
+	Before []Face // each of Faces before the edit
+	After  []Face // each of Faces after the edit
+}
+
+func (e Edit) String() string {
+	var parts []string
+	for i, face := range e.Faces {
+		parts = append(parts, fmt.Sprintf("face %d %d -> %d pips %v value %d", face, e.Before[i].NumPips(), e.After[i].NumPips(), e.After[i].pips, e.After[i].BaseValue()))
+	}
+	return fmt.Sprintf("%s: %v", e.Op, parts)
+}
+
+// returns a copy of the face that shares nothing with f
+func (f *Face) Clone() Face {
+	return Face{pips: slices.Clone(f.pips), alts: slices.Clone(f.alts), offset: f.offset}
+}
+
+// returns a copy of the die that shares nothing with d, edits to one never show up on the other
+func (d *Die) Clone() Die {
+	clone := Die{activeFace: d.activeFace, weights: slices.Clone(d.weights)}
+	for i := range d.faces {
+		clone.faces = append(clone.faces, d.faces[i].Clone())
+	}
+	return clone
+}
+
+func (d *Die) validFace(op EditOp, face int) error {
+	if face < 0 || face >= len(d.faces) {
+		return &EditError{Op: op, Face: face, Err: ErrInvalidFace}
+	}
+	return nil
+}
+
+// replaces faces with after and records what they were.
+//
+// copies of the die made before the edit keep their faces
+func (d *Die) apply(op EditOp, faces []int, after []Face) Edit {
+	edit := Edit{Op: op, Faces: faces, After: after}
+	d.faces = slices.Clone(d.faces)
+	for i, face := range faces {
+		edit.Before = append(edit.Before, d.faces[face].Clone())
+		d.faces[face] = after[i].Clone()
+	}
+	return edit
+}
+
+// adds a pip carrying mod to the end of a face
+func (d *Die) AddPip(face int, mod Modifier) (Edit, error) {
+	if err := d.validFace(EditAddPip, face); err != nil {
+		return Edit{}, err
+	}
+	if !mod.Valid() {
+		return Edit{}, &EditError{Op: EditAddPip, Face: face, Err: ErrUnknownModifier}
+	}
+	if d.faces[face].NumPips() >= MAX_PIPS {
+		return Edit{}, &EditError{Op: EditAddPip, Face: face, Err: ErrTooManyPips}
+	}
+	if err := validValue(d.faces[face].BaseValue() + 1); err != nil {
+		return Edit{}, &EditError{Op: EditAddPip, Face: face, Err: err}
+	}
+
+	after := d.faces[face].Clone()
+	after.pips = append(after.pips, mod)
+	return d.apply(EditAddPip, []int{face}, []Face{after}), nil
+}
+
+// removes the pip at index pip, and its Modifier. the last pip on a face can't be removed
+func (d *Die) RemovePip(face int, pip int) (Edit, error) {
+	if err := d.validFace(EditRemovePip, face); err != nil {
+		return Edit{}, err
+	}
+	if pip < 0 || pip >= d.faces[face].NumPips() {
+		return Edit{}, &EditError{Op: EditRemovePip, Face: face, Err: ErrInvalidPip}
+	}
+	if d.faces[face].NumPips() == 1 {
+		return Edit{}, &EditError{Op: EditRemovePip, Face: face, Err: ErrNoPips}
+	}
+	if err := validValue(d.faces[face].BaseValue() - 1); err != nil {
+		return Edit{}, &EditError{Op: EditRemovePip, Face: face, Err: err}
+	}
+
+	after := d.faces[face].Clone()
+	after.pips = slices.Delete(after.pips, pip, pip+1)
+	return d.apply(EditRemovePip, []int{face}, []Face{after}), nil
+}
+
+// gives a face exactly pips pips. new pips are ModNONE, removed pips come off the end
+func (d *Die) SetPips(face int, pips int) (Edit, error) {
+	if err := d.validFace(EditSetPips, face); err != nil {
+		return Edit{}, err
+	}
+	if err := validPips(pips); err != nil {
+		return Edit{}, &EditError{Op: EditSetPips, Face: face, Err: err}
+	}
+	if err := validValue(pips + d.faces[face].offset); err != nil {
+		return Edit{}, &EditError{Op: EditSetPips, Face: face, Err: err}
+	}
+
+	after := d.faces[face].Clone()
+	for len(after.pips) < pips {
+		after.pips = append(after.pips, ModNONE)
+	}
+	after.pips = after.pips[:pips]
+	return d.apply(EditSetPips, []int{face}, []Face{after}), nil
+}
+
+// swaps what is on faces a and b. base weights stay with their side of the die
+func (d *Die) SwapFaces(a, b int) (Edit, error) {
+	if err := d.validFace(EditSwapFaces, a); err != nil {
+		return Edit{}, err
+	}
+	if err := d.validFace(EditSwapFaces, b); err != nil {
+		return Edit{}, err
+	}
+
+	return d.apply(EditSwapFaces, []int{a, b}, []Face{d.faces[b], d.faces[a]}), nil
+}
+
+// replaces face with a copy of fromFace on another die (or this one)
+func (d *Die) CopyFace(face int, from *Die, fromFace int) (Edit, error) {
+	if err := d.validFace(EditCopyFace, face); err != nil {
+		return Edit{}, err
+	}
+	if fromFace < 0 || fromFace >= len(from.faces) {
+		return Edit{}, &EditError{Op: EditCopyFace, Face: fromFace, Err: ErrInvalidFace}
+	}
+
+	return d.apply(EditCopyFace, []int{face}, []Face{from.faces[fromFace]}), nil
+}
+
+// puts mod on the pip at index pip, replacing whatever Modifier it had
+func (d *Die) SetModifier(face int, pip int, mod Modifier) (Edit, error) {
+	if err := d.validFace(EditSetModifier, face); err != nil {
+		return Edit{}, err
+	}
+	if pip < 0 || pip >= d.faces[face].NumPips() {
+		return Edit{}, &EditError{Op: EditSetModifier, Face: face, Err: ErrInvalidPip}
+	}
+	if !mod.Valid() {
+		return Edit{}, &EditError{Op: EditSetModifier, Face: face, Err: ErrUnknownModifier}
+	}
+
+	after := d.faces[face].Clone()
+	after.pips[pip] = mod
+	return d.apply(EditSetModifier, []int{face}, []Face{after}), nil
+}
+
+// makes a face count as value without changing its pips. pips added or removed later still move the value by 1
+func (d *Die) SetValue(face int, value int) (Edit, error) {
+	if err := d.validFace(EditSetValue, face); err != nil {
+		return Edit{}, err
+	}
+	if err := validValue(value); err != nil {
+		return Edit{}, &EditError{Op: EditSetValue, Face: face, Err: err}
+	}
+
+	after := d.faces[face].Clone()
+	after.offset = value - after.NumPips()
+	return d.apply(EditSetValue, []int{face}, []Face{after}), nil
+}
+
+// puts faces back to what they were before edit. edits are undone newest first,
+// ErrStaleEdit when the faces don't look like they did right after edit
+func (d *Die) Undo(edit Edit) error {
+	return d.restore(edit, edit.After, edit.Before)
+}
+
+// makes an undone edit again
+func (d *Die) Redo(edit Edit) error {
+	return d.restore(edit, edit.Before, edit.After)
+}
+
+func (d *Die) restore(edit Edit, from, to []Face) error {
+	for i, face := range edit.Faces {
+		if err := d.validFace(edit.Op, face); err != nil {
+			return err
+		}
+		if !d.faces[face].equal(&from[i]) {
+			return &EditError{Op: edit.Op, Face: face, Err: ErrStaleEdit}
+		}
+	}
+
+	d.faces = slices.Clone(d.faces)
+	for i, face := range edit.Faces {
+		d.faces[face] = to[i].Clone()
+	}
+	return nil
+}
+
+func (f *Face) equal(other *Face) bool {
+	return slices.Equal(f.pips, other.pips) && slices.Equal(f.alts, other.alts) && f.offset == other.offset
+}
@@ -0,0 +1,193 @@
+package dice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewFaceErrors(t *testing.T) {
+	for _, pips := range []int{0, -1, MAX_PIPS + 1} {
+		if _, err := NewFace(pips); err == nil {
+			t.Errorf("NewFace(%d) should fail", pips)
+		}
+	}
+	if _, err := NewFace(MAX_PIPS + 1); !errors.Is(err, ErrTooManyPips) {
+		t.Errorf("NewFace(%d) error = %v; want %v", MAX_PIPS+1, err, ErrTooManyPips)
+	}
+	if _, err := NewFace(0); !errors.Is(err, ErrNoPips) {
+		t.Errorf("NewFace(0) error = %v; want %v", err, ErrNoPips)
+	}
+	if _, err := NewSplitFace(2, MAX_FACE_VALUE+1); !errors.Is(err, ErrInvalidAlt) {
+		t.Errorf("NewSplitFace(2, %d) error = %v; want %v", MAX_FACE_VALUE+1, err, ErrInvalidAlt)
+	}
+	if _, err := NewSplitFace(2, 20, 0, MIN_FACE_VALUE); err != nil {
+		t.Errorf("NewSplitFace(2, 20, 0, %d) error = %v", MIN_FACE_VALUE, err)
+	}
+}
+
+func TestEdits(t *testing.T) {
+	other := New6SidedDie([6]int{9, 9, 9, 9, 9, 9})
+
+	tests := []struct {
+		name  string
+		edit  func(d *Die) (Edit, error)
+		faces []Face // every face after the edit
+	}{
+		{"add pip", func(d *Die) (Edit, error) { return d.AddPip(0, ModBONUS) },
+			[]Face{NewModFace(ModNONE, ModBONUS), MustFace(2), NewModFace(ModDOUBLE, ModNONE, ModGOLD)}},
+		{"remove pip", func(d *Die) (Edit, error) { return d.RemovePip(2, 0) },
+			[]Face{MustFace(1), MustFace(2), NewModFace(ModNONE, ModGOLD)}},
+		{"set pips more", func(d *Die) (Edit, error) { return d.SetPips(2, 5) },
+			[]Face{MustFace(1), MustFace(2), NewModFace(ModDOUBLE, ModNONE, ModGOLD, ModNONE, ModNONE)}},
+		{"set pips less", func(d *Die) (Edit, error) { return d.SetPips(2, 1) },
+			[]Face{MustFace(1), MustFace(2), NewModFace(ModDOUBLE)}},
+		{"swap faces", func(d *Die) (Edit, error) { return d.SwapFaces(0, 2) },
+			[]Face{NewModFace(ModDOUBLE, ModNONE, ModGOLD), MustFace(2), MustFace(1)}},
+		{"copy face", func(d *Die) (Edit, error) { return d.CopyFace(1, &other, 3) },
+			[]Face{MustFace(1), MustFace(9), NewModFace(ModDOUBLE, ModNONE, ModGOLD)}},
+		{"set modifier", func(d *Die) (Edit, error) { return d.SetModifier(1, 1, ModWILD) },
+			[]Face{MustFace(1), NewModFace(ModNONE, ModWILD), NewModFace(ModDOUBLE, ModNONE, ModGOLD)}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			die := Die{faces: []Face{MustFace(1), MustFace(2), NewModFace(ModDOUBLE, ModNONE, ModGOLD)}}
+			original := die.Clone()
+			copied := die // made before the edit, keeps the old faces
+
+			edit, err := tc.edit(&die)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !reflect.DeepEqual(die.faces, tc.faces) {
+				t.Errorf("faces = %v; want %v", die.faces, tc.faces)
+			}
+			if !reflect.DeepEqual(copied.faces, original.faces) {
+				t.Errorf("edit changed a copy of the die %v", copied.faces)
+			}
+
+			if err := die.Undo(edit); err != nil {
+				t.Fatalf("Undo() error = %v", err)
+			}
+			if !reflect.DeepEqual(die.faces, original.faces) {
+				t.Errorf("after Undo() faces = %v; want %v", die.faces, original.faces)
+			}
+			if err := die.Undo(edit); !errors.Is(err, ErrStaleEdit) {
+				t.Errorf("second Undo() error = %v; want %v", err, ErrStaleEdit)
+			}
+
+			if err := die.Redo(edit); err != nil {
+				t.Fatalf("Redo() error = %v", err)
+			}
+			if !reflect.DeepEqual(die.faces, tc.faces) {
+				t.Errorf("after Redo() faces = %v; want %v", die.faces, tc.faces)
+			}
+		})
+	}
+}
+
+func TestEditErrors(t *testing.T) {
+	full := NewModFace(ModNONE, ModNONE, ModNONE, ModNONE, ModNONE, ModNONE, ModNONE, ModNONE, ModNONE)
+	other := NewDie(2)
+
+	tests := []struct {
+		name string
+		edit func(d *Die) (Edit, error)
+		op   EditOp
+		want error
+	}{
+		{"add to full face", func(d *Die) (Edit, error) { return d.AddPip(1, ModNONE) }, EditAddPip, ErrTooManyPips},
+		{"add unknown modifier", func(d *Die) (Edit, error) { return d.AddPip(0, NUM_MODIFIERS) }, EditAddPip, ErrUnknownModifier},
+		{"add to missing face", func(d *Die) (Edit, error) { return d.AddPip(2, ModNONE) }, EditAddPip, ErrInvalidFace},
+		{"remove last pip", func(d *Die) (Edit, error) { return d.RemovePip(0, 0) }, EditRemovePip, ErrNoPips},
+		{"remove missing pip", func(d *Die) (Edit, error) { return d.RemovePip(1, 9) }, EditRemovePip, ErrInvalidPip},
+		{"set 0 pips", func(d *Die) (Edit, error) { return d.SetPips(1, 0) }, EditSetPips, ErrNoPips},
+		{"set too many pips", func(d *Die) (Edit, error) { return d.SetPips(0, MAX_PIPS+1) }, EditSetPips, ErrTooManyPips},
+		{"swap missing face", func(d *Die) (Edit, error) { return d.SwapFaces(0, -1) }, EditSwapFaces, ErrInvalidFace},
+		{"copy missing face", func(d *Die) (Edit, error) { return d.CopyFace(0, &other, 2) }, EditCopyFace, ErrInvalidFace},
+		{"set modifier on missing pip", func(d *Die) (Edit, error) { return d.SetModifier(0, 1, ModBONUS) }, EditSetModifier, ErrInvalidPip},
+		{"set unknown modifier", func(d *Die) (Edit, error) { return d.SetModifier(0, 0, NUM_MODIFIERS) }, EditSetModifier, ErrUnknownModifier},
+		{"set value too high", func(d *Die) (Edit, error) { return d.SetValue(0, MAX_FACE_VALUE+1) }, EditSetValue, ErrInvalidValue},
+		{"set value on missing face", func(d *Die) (Edit, error) { return d.SetValue(2, 3) }, EditSetValue, ErrInvalidFace},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			die := Die{faces: []Face{MustFace(1), full}}
+			before := die.Clone()
+
+			_, err := tc.edit(&die)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v; want %v", err, tc.want)
+			}
+			var editErr *EditError
+			if !errors.As(err, &editErr) || editErr.Op != tc.op {
+				t.Errorf("error = %#v; want an *EditError for %s", err, tc.op)
+			}
+			if !reflect.DeepEqual(die, before) {
+				t.Errorf("failed edit changed the die %v", die.faces)
+			}
+		})
+	}
+}
+
+func TestEditHistory(t *testing.T) {
+	die := NewDie(6)
+	original := die.Clone()
+
+	var history []Edit
+	for _, edit := range []func() (Edit, error){
+		func() (Edit, error) { return die.AddPip(0, ModBONUS) },
+		func() (Edit, error) { return die.SetModifier(0, 0, ModGOLD) },
+		func() (Edit, error) { return die.SwapFaces(0, 5) },
+	} {
+		e, err := edit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		history = append(history, e)
+	}
+
+	// undone newest first
+	for i := len(history) - 1; i >= 0; i-- {
+		if err := die.Undo(history[i]); err != nil {
+			t.Fatalf("Undo(%s) error = %v", history[i], err)
+		}
+	}
+	if !reflect.DeepEqual(die, original) {
+		t.Errorf("after undoing everything\ngot  %v\nwant %v", die.faces, original.faces)
+	}
+}
+
+// the value moves apart from the pips, pips added after still count
+func TestSetValue(t *testing.T) {
+	die := Die{faces: []Face{MustFace(3)}}
+	edit, err := die.SetValue(0, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if face := die.Face(0); face.Value() != 12 || face.NumPips() != 3 {
+		t.Fatalf("after SetValue(12) value %d with %d pips; want 12 with 3", face.Value(), face.NumPips())
+	}
+
+	if _, err := die.AddPip(0, ModNONE); err != nil {
+		t.Fatal(err)
+	}
+	if face := die.Face(0); face.Value() != 13 || face.NumPips() != 4 {
+		t.Errorf("after AddPip value %d with %d pips; want 13 with 4", face.Value(), face.NumPips())
+	}
+
+	if err := die.Undo(edit); !errors.Is(err, ErrStaleEdit) {
+		t.Errorf("Undo() of an older edit = %v; want %v", err, ErrStaleEdit)
+	}
+
+	die = Die{faces: []Face{MustFace(3)}}
+	edit, _ = die.SetValue(0, -2)
+	if err := die.Undo(edit); err != nil {
+		t.Fatal(err)
+	}
+	if face := die.Face(0); face.Value() != 3 {
+		t.Errorf("after Undo value %d; want 3", face.Value())
+	}
+}
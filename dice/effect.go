@@ -0,0 +1,89 @@
+package dice
+
+// ScoreMoment is when a ScoreEffect triggers while a hand is scored
+type ScoreMoment uint8
+
+const (
+	OnDieScored  ScoreMoment = iota // once for every scored die, after the die's own pips
+	OnHandScored                    // once for the hand, after every die and before Total
+)
+
+// ScoreEffect is anything outside of the dice that changes a hand while it is scored, like jewelry.
+//
+// Trigger returns what the effect does, ok is false when it doesn't trigger.
+// die is the DieScore being scored for OnDieScored and nil for OnHandScored
+type ScoreEffect interface {
+	Moment() ScoreMoment
+	Trigger(result *ScoreResult, die *DieScore) (score EffectScore, ok bool)
+}
+
+// EffectScore is what one ScoreEffect did to a scored hand
+type EffectScore struct {
+	Source string  // what triggered, "Ruby Ring"
+	Slot   int     // where the source is equipped, effects resolve in slot order
+	Die    int     // DieScore.Index of the die it triggered on, -1 for the hand
+	Added  float32 // added to the die's Score, or the hand's Base
+	Mult   float32 // multiplies the die's Score, or the hand's Mult. 0 for no change
+	Gold   int
+}
+
+// ApplyEffects runs effects on a scored hand and works out its Total again.
+//
+// every die is done in order, with each of its OnDieScored effects in the order given, then
+// every OnHandScored effect in the order given. each effect that triggers is added to result.Effects
+//
+//	die.Score = int((die.Score + Added) * Mult)
+//	Base = sum of every die.Score, then + Added of each hand effect
+//	Mult = Mult * Mult of each hand effect
+func (r *ScoreResult) ApplyEffects(effects []ScoreEffect) {
+	for d := range r.Dice {
+		die := &r.Dice[d]
+		for _, effect := range effects {
+			if effect.Moment() != OnDieScored {
+				continue
+			}
+			if score, ok := effect.Trigger(r, die); ok {
+				r.Apply(score, die)
+			}
+		}
+	}
+
+	r.Base, r.Gold = 0, 0
+	for _, die := range r.Dice {
+		r.Base += die.Score
+		r.Gold += die.Gold
+	}
+
+	for _, effect := range effects {
+		if effect.Moment() != OnHandScored {
+			continue
+		}
+		if score, ok := effect.Trigger(r, nil); ok {
+			r.Apply(score, nil)
+		}
+	}
+
+	r.Total = int(float32(r.Base) * r.Mult)
+}
+
+// Apply does what one EffectScore says to die, or to the hand when die is nil, and adds it to r.Effects.
+// the arithmetic of ApplyEffects. a die's Gold isn't added to the hand's, and Total isn't worked out again
+func (r *ScoreResult) Apply(score EffectScore, die *DieScore) {
+	if die != nil {
+		score.Die = die.Index
+		value := float32(die.Score) + score.Added
+		if score.Mult != 0 {
+			value *= score.Mult
+		}
+		die.Score = max(int(value), 0)
+		die.Gold += score.Gold
+	} else {
+		score.Die = -1
+		r.Base = max(int(float32(r.Base)+score.Added), 0)
+		if score.Mult != 0 {
+			r.Mult *= score.Mult
+		}
+		r.Gold += score.Gold
+	}
+	r.Effects = append(r.Effects, score)
+}
@@ -0,0 +1,64 @@
+package dice
+
+import "testing"
+
+// adds to dice counting as value, or to the hand when value is 0
+type testEffect struct {
+	name   string
+	value  int
+	added  float32
+	mult   float32
+	moment ScoreMoment
+}
+
+func (e testEffect) Moment() ScoreMoment { return e.moment }
+
+func (e testEffect) Trigger(result *ScoreResult, die *DieScore) (EffectScore, bool) {
+	if die != nil && die.Value != e.value {
+		return EffectScore{}, false
+	}
+	return EffectScore{Source: e.name, Added: e.added, Mult: e.mult}, true
+}
+
+func TestApplyEffects(t *testing.T) {
+	dice := []Die{
+		dieShowing(MustFace(2)),
+		dieShowing(MustFace(2)),
+		dieShowing(MustFace(5)),
+	}
+	result := ScoreHandBreakdown(dice, ONE_PAIR, nil)
+	mult := result.Mult
+
+	result.ApplyEffects([]ScoreEffect{
+		testEffect{name: "hand x2", mult: 2, moment: OnHandScored},
+		testEffect{name: "twos +2", value: 2, added: 2, moment: OnDieScored},
+		testEffect{name: "twos x1.5", value: 2, mult: 1.5, moment: OnDieScored},
+		testEffect{name: "hand +1", added: 1, moment: OnHandScored},
+	})
+
+	// (2 + 2) * 1.5 = 6 for each 2, then 6 + 6 + 5 + 1
+	if result.Dice[0].Score != 6 || result.Dice[1].Score != 6 || result.Dice[2].Score != 5 {
+		t.Errorf("die scores %d %d %d; want 6 6 5", result.Dice[0].Score, result.Dice[1].Score, result.Dice[2].Score)
+	}
+	if result.Base != 18 || result.Mult != mult*2 || result.Total != int(18*mult*2) {
+		t.Errorf("Base %d Mult %.2f Total %d; want 18 %.2f %d", result.Base, result.Mult, result.Total, mult*2, int(18*mult*2))
+	}
+
+	// every die's effects in order, then the hand's
+	want := []struct {
+		source string
+		die    int
+	}{
+		{"twos +2", 0}, {"twos x1.5", 0},
+		{"twos +2", 1}, {"twos x1.5", 1},
+		{"hand x2", -1}, {"hand +1", -1},
+	}
+	if len(result.Effects) != len(want) {
+		t.Fatalf("%d effects triggered; want %d\n%v", len(result.Effects), len(want), result.Effects)
+	}
+	for i, w := range want {
+		if result.Effects[i].Source != w.source || result.Effects[i].Die != w.die {
+			t.Errorf("effect %d = %+v; want %s on die %d", i, result.Effects[i], w.source, w.die)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package dice
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dice, faces and modifiers can be saved as JSON (save files, data files) or
+// as compact binary (the dice code players copy and paste).
+//
+// every encoding starts with ENCODING_VERSION. bump it when the format changes
+// and keep decoding the older versions
+//
+//	1. faces and the active face
+//	2. + base weights of loaded dice
+//	3. + base values of faces that don't count as their pips
+
+const ENCODING_VERSION = 3
+
+// oldest version that can still be decoded
+const MIN_ENCODING_VERSION = 1
+
+func validVersion(version int) bool {
+	return version >= MIN_ENCODING_VERSION && version <= ENCODING_VERSION
+}
+
+// prefix of every dice code, the version is added after it. "DWR3-..."
+const DICE_CODE_PREFIX = "DWR"
+
+var (
+	ErrEncodingVersion = errors.New("unknown encoding version")
+	ErrInvalidEncoding = errors.New("invalid encoding")
+)
+
+func invalidEncoding(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidEncoding, fmt.Sprintf(format, args...))
+}
+
+// returns the Modifier with the given name, see Modifier.String
+func ParseModifier(name string) (Modifier, error) {
+	for mod := range NUM_MODIFIERS {
+		if strings.EqualFold(modifierTable[mod].name, name) {
+			return mod, nil
+		}
+	}
+	return ModNONE, invalidEncoding("unknown modifier %q", name)
+}
+
+// modifiers are saved by name so reordering the enum doesn't break saves
+func (m Modifier) MarshalText() ([]byte, error) {
+	if !m.Valid() {
+		return nil, invalidEncoding("unknown modifier %d", uint8(m))
+	}
+	return []byte(m.String()), nil
+}
+
+func (m *Modifier) UnmarshalText(text []byte) error {
+	mod, err := ParseModifier(string(text))
+	if err != nil {
+		return err
+	}
+	*m = mod
+	return nil
+}
+
+// checks everything a Face constructor would
+func (f *Face) validate() error {
+	if len(f.pips) < 1 || len(f.pips) > MAX_PIPS {
+		return invalidEncoding("face has %d pips. Must be between 1 - %d", len(f.pips), MAX_PIPS)
+	}
+	for i, mod := range f.pips {
+		if !mod.Valid() {
+			return invalidEncoding("pip %d has unknown modifier %d", i, uint8(mod))
+		}
+	}
+	for _, alt := range f.alts {
+		if validValue(alt) != nil {
+			return invalidEncoding("face counts as %d. Must be between %d - %d", alt, MIN_FACE_VALUE, MAX_FACE_VALUE)
+		}
+	}
+	if value := f.BaseValue(); validValue(value) != nil {
+		return invalidEncoding("face has a value of %d. Must be between %d - %d", value, MIN_FACE_VALUE, MAX_FACE_VALUE)
+	}
+	return nil
+}
+
+func (d *Die) validate() error {
+	if len(d.faces) == 0 {
+		return invalidEncoding("die has no faces")
+	}
+	if d.activeFace < 0 || d.activeFace >= len(d.faces) {
+		return invalidEncoding("active face %d of a %d sided die", d.activeFace, len(d.faces))
+	}
+	for i := range d.faces {
+		if err := d.faces[i].validate(); err != nil {
+			return fmt.Errorf("face %d: %w", i, err)
+		}
+	}
+	if d.weights != nil && len(d.weights) != len(d.faces) {
+		return invalidEncoding("%d weights for a %d sided die", len(d.weights), len(d.faces))
+	}
+	for i, weight := range d.weights {
+		if weight < 0 || math.IsNaN(float64(weight)) || math.IsInf(float64(weight), 0) {
+			return invalidEncoding("face %d weighs %v", i, weight)
+		}
+	}
+	return nil
+}
+
+// JSON
+
+type faceJSON struct {
+	Pips  []Modifier `json:"pips"`
+	Alts  []int      `json:"alts,omitempty"`
+	Value *int       `json:"value,omitempty"` // version 3+, only when it isn't the number of pips
+}
+
+type dieJSON struct {
+	Version int        `json:"version"`
+	Active  int        `json:"active"`
+	Faces   []faceJSON `json:"faces"`
+	Weights []float32  `json:"weights,omitempty"` // version 2+, only for loaded dice
+}
+
+func (f *Face) toJSON() faceJSON {
+	fj := faceJSON{Pips: f.Modifiers(), Alts: append([]int(nil), f.alts...)}
+	if f.offset != 0 {
+		value := f.BaseValue()
+		fj.Value = &value
+	}
+	return fj
+}
+
+func (fj faceJSON) face() Face {
+	face := Face{pips: fj.Pips, alts: fj.Alts}
+	if fj.Value != nil {
+		face.offset = *fj.Value - len(fj.Pips)
+	}
+	return face
+}
+
+// a face is saved as its pips' modifiers, alts and value when it isn't the number of pips
+//
+//	{"pips": ["None", "Bonus", "None"], "alts": [5]}
+//	{"pips": ["None", "None"], "value": 20}
+func (f Face) MarshalJSON() ([]byte, error) {
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(f.toJSON())
+}
+
+func (f *Face) UnmarshalJSON(data []byte) error {
+	var fj faceJSON
+	if err := json.Unmarshal(data, &fj); err != nil {
+		return err
+	}
+	face := fj.face()
+	if err := face.validate(); err != nil {
+		return err
+	}
+	*f = face
+	return nil
+}
+
+// a die is saved with the encoding version and the face that is showing
+//
+//	{"version": 3, "active": 0, "faces": [{"pips": ["None"]}, ...], "weights": [1, 1, 3, ...]}
+func (d Die) MarshalJSON() ([]byte, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	dj := dieJSON{
+		Version: ENCODING_VERSION,
+		Active:  d.activeFace,
+		Faces:   make([]faceJSON, len(d.faces)),
+		Weights: d.weights,
+	}
+	for i := range d.faces {
+		dj.Faces[i] = d.faces[i].toJSON()
+	}
+	return json.Marshal(dj)
+}
+
+func (d *Die) UnmarshalJSON(data []byte) error {
+	var dj dieJSON
+	if err := json.Unmarshal(data, &dj); err != nil {
+		return err
+	}
+	if !validVersion(dj.Version) {
+		return fmt.Errorf("%w %d", ErrEncodingVersion, dj.Version)
+	}
+
+	die := Die{activeFace: dj.Active, faces: make([]Face, len(dj.Faces)), weights: dj.Weights}
+	for i, fj := range dj.Faces {
+		die.faces[i] = fj.face()
+	}
+	if err := die.validate(); err != nil {
+		return err
+	}
+	*d = die
+	return nil
+}
+
+// binary
+//
+//	die:  [version] [active face] [number of faces] face... [number of weights] weight...
+//	face: [number of pips] modifier... [number of alts] alt... [base value]
+//
+// every field is a single byte, except weights which are 4 byte big endian float32s.
+// alts and the base value are signed bytes. version 1 dice have no weights, version 1 and 2 faces have no base value
+
+func (f *Face) appendBinary(b []byte) []byte {
+	b = append(b, byte(len(f.pips)))
+	for _, mod := range f.pips {
+		b = append(b, byte(mod))
+	}
+	b = append(b, byte(len(f.alts)))
+	for _, alt := range f.alts {
+		b = append(b, byte(int8(alt)))
+	}
+	b = append(b, byte(int8(f.BaseValue())))
+	return b
+}
+
+// reads bytes in order, remembering the first thing that went wrong
+type byteReader struct {
+	data    []byte
+	err     error
+	version int
+}
+
+func (r *byteReader) next() int {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) == 0 {
+		r.err = invalidEncoding("ran out of bytes")
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return int(b)
+}
+
+func (r *byteReader) float32() float32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) < 4 {
+		r.err = invalidEncoding("ran out of bytes")
+		return 0
+	}
+	bits := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return math.Float32frombits(bits)
+}
+
+func (r *byteReader) face() Face {
+	var face Face
+	numPips := r.next()
+	for range numPips {
+		face.pips = append(face.pips, Modifier(r.next()))
+	}
+	numAlts := r.next()
+	for range numAlts {
+		face.alts = append(face.alts, int(int8(r.next())))
+	}
+	if r.version >= 3 {
+		face.offset = int(int8(r.next())) - numPips
+	}
+	return face
+}
+
+// the die without the version byte
+func (d *Die) appendBinary(b []byte) []byte {
+	b = append(b, byte(d.activeFace), byte(len(d.faces)))
+	for i := range d.faces {
+		b = d.faces[i].appendBinary(b)
+	}
+	b = append(b, byte(len(d.weights)))
+	for _, weight := range d.weights {
+		b = binary.BigEndian.AppendUint32(b, math.Float32bits(weight))
+	}
+	return b
+}
+
+func (r *byteReader) die() (Die, error) {
+	die := Die{activeFace: r.next()}
+	numFaces := r.next()
+	for range numFaces {
+		die.faces = append(die.faces, r.face())
+	}
+	if r.version >= 2 {
+		numWeights := r.next()
+		for range numWeights {
+			die.weights = append(die.weights, r.float32())
+		}
+	}
+	if r.err != nil {
+		return Die{}, r.err
+	}
+	return die, die.validate()
+}
+
+// reads the version byte, the rest is read the way that version wrote it
+func (r *byteReader) readVersion() error {
+	r.version = r.next()
+	if r.err == nil && !validVersion(r.version) {
+		return fmt.Errorf("%w %d", ErrEncodingVersion, r.version)
+	}
+	return r.err
+}
+
+func (d Die) MarshalBinary() ([]byte, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	return d.appendBinary([]byte{ENCODING_VERSION}), nil
+}
+
+func (d *Die) UnmarshalBinary(data []byte) error {
+	r := &byteReader{data: data}
+	if err := r.readVersion(); err != nil {
+		return err
+	}
+	die, err := r.die()
+	if err != nil {
+		return err
+	}
+	if len(r.data) > 0 {
+		return invalidEncoding("%d bytes left over", len(r.data))
+	}
+	*d = die
+	return nil
+}
+
+// dice codes
+
+// EncodeDiceCode turns a loadout into a short string that can be copied and pasted
+//
+//	DWR3-AwMABgEAAAEC...
+func EncodeDiceCode(dice []Die) (string, error) {
+	if len(dice) > 255 {
+		return "", invalidEncoding("%d dice is too many for a dice code", len(dice))
+	}
+
+	b := []byte{ENCODING_VERSION, byte(len(dice))}
+	for i := range dice {
+		if err := dice[i].validate(); err != nil {
+			return "", fmt.Errorf("die %d: %w", i, err)
+		}
+		b = dice[i].appendBinary(b)
+	}
+	return fmt.Sprintf("%s%d-%s", DICE_CODE_PREFIX, ENCODING_VERSION, base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+// DecodeDiceCode reads the loadout from EncodeDiceCode, codes from older versions still work.
+// surrounding whitespace is ignored
+func DecodeDiceCode(code string) ([]Die, error) {
+	prefix, data, ok := strings.Cut(strings.TrimSpace(code), "-")
+	if !ok || !strings.HasPrefix(prefix, DICE_CODE_PREFIX) {
+		return nil, invalidEncoding("not a dice code")
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(prefix, DICE_CODE_PREFIX))
+	if err != nil || !validVersion(version) {
+		return nil, fmt.Errorf("%w %s", ErrEncodingVersion, strings.TrimPrefix(prefix, DICE_CODE_PREFIX))
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, invalidEncoding("%v", err)
+	}
+
+	r := &byteReader{data: b}
+	if err := r.readVersion(); err != nil {
+		return nil, err
+	}
+	if r.version != version {
+		return nil, invalidEncoding("%s code holds version %d dice", prefix, r.version)
+	}
+	numDice := r.next()
+	if r.err != nil {
+		return nil, r.err
+	}
+	dice := make([]Die, 0, numDice)
+	for i := range numDice {
+		die, err := r.die()
+		if err != nil {
+			return nil, fmt.Errorf("die %d: %w", i, err)
+		}
+		dice = append(dice, die)
+	}
+	if len(r.data) > 0 {
+		return nil, invalidEncoding("%d bytes left over", len(r.data))
+	}
+	return dice, nil
+}
@@ -0,0 +1,192 @@
+package dice
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func encodingTestDice() []Die {
+	loaded := NewDie(6)
+	if err := loaded.SetWeight(5, 2.5); err != nil {
+		panic(err)
+	}
+
+	return []Die{
+		NewDie(6),
+		{activeFace: 2, faces: []Face{
+			NewModFace(ModWILD),
+			NewModFace(ModNONE, ModBONUS, ModDOUBLE),
+			MustSplitFace(2, 5),
+			MustSplitFace(1, 20, -3),
+			NewModFace(ModGOLD, ModHOLLOW, ModCRACKED, ModDULL, ModMULT, ModBONUS_BIG, ModNONE, ModNONE, ModNONE),
+		}},
+		New6SidedDie([6]int{1, 1, 9, 9, 3, 3}),
+		loaded,
+		NewDie(20),
+		{faces: []Face{MustValueFace(1, 0), MustValueFace(3, -4), MustValueFace(9, 99), MustValueFace(2, -99)}},
+	}
+}
+
+func TestDieJSONRoundTrip(t *testing.T) {
+	for i, die := range encodingTestDice() {
+		data, err := json.Marshal(die)
+		if err != nil {
+			t.Fatalf("die %d: Marshal() error = %v", i, err)
+		}
+
+		var got Die
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("die %d: Unmarshal(%s) error = %v", i, data, err)
+		}
+		if !reflect.DeepEqual(got, die) {
+			t.Errorf("die %d: round trip through %s\ngot  %+v\nwant %+v", i, data, got, die)
+		}
+	}
+}
+
+func TestFaceJSON(t *testing.T) {
+	face := NewModFace(ModNONE, ModBONUS, ModNONE)
+	data, err := json.Marshal(face)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"pips":["None","Bonus","None"]}`; string(data) != want {
+		t.Errorf("Marshal() = %s; want %s", data, want)
+	}
+
+	var got Face
+	if err := json.Unmarshal([]byte(`{"pips":["none","big bonus"],"alts":[7]}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, Face{pips: []Modifier{ModNONE, ModBONUS_BIG}, alts: []int{7}}) {
+		t.Errorf("Unmarshal() = %+v", got)
+	}
+}
+
+func TestDieBinaryRoundTrip(t *testing.T) {
+	for i, die := range encodingTestDice() {
+		data, err := die.MarshalBinary()
+		if err != nil {
+			t.Fatalf("die %d: MarshalBinary() error = %v", i, err)
+		}
+
+		var got Die
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("die %d: UnmarshalBinary(%v) error = %v", i, data, err)
+		}
+		if !reflect.DeepEqual(got, die) {
+			t.Errorf("die %d: round trip through %v\ngot  %+v\nwant %+v", i, data, got, die)
+		}
+	}
+}
+
+func TestDiceCodeRoundTrip(t *testing.T) {
+	dice := encodingTestDice()
+	code, err := EncodeDiceCode(dice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(code, "DWR3-") {
+		t.Errorf("code %q should start with DWR3-", code)
+	}
+
+	got, err := DecodeDiceCode("  " + code + "\n")
+	if err != nil {
+		t.Fatalf("DecodeDiceCode(%q) error = %v", code, err)
+	}
+	if !reflect.DeepEqual(got, dice) {
+		t.Errorf("round trip through %q\ngot  %+v\nwant %+v", code, got, dice)
+	}
+}
+
+func TestDecodeVersion1(t *testing.T) {
+	want := Die{faces: []Face{MustFace(1), NewModFace(ModNONE, ModBONUS)}}
+
+	var die Die
+	if err := json.Unmarshal([]byte(`{"version":1,"active":0,"faces":[{"pips":["None"]},{"pips":["None","Bonus"]}]}`), &die); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(die, want) {
+		t.Errorf("Unmarshal() = %+v; want %+v", die, want)
+	}
+
+	die = Die{}
+	if err := die.UnmarshalBinary([]byte{1, 0, 2, 1, 0, 0, 2, 0, 1, 0}); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if !reflect.DeepEqual(die, want) {
+		t.Errorf("UnmarshalBinary() = %+v; want %+v", die, want)
+	}
+
+	// [version] [number of dice] die...
+	code := "DWR1-" + base64.RawURLEncoding.EncodeToString([]byte{1, 1, 0, 2, 1, 0, 0, 2, 0, 1, 0})
+	dice, err := DecodeDiceCode(code)
+	if err != nil {
+		t.Fatalf("DecodeDiceCode(%q) error = %v", code, err)
+	}
+	if !reflect.DeepEqual(dice, []Die{want}) {
+		t.Errorf("DecodeDiceCode(%q) = %+v; want %+v", code, dice, want)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	jsonTests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{"future version", `{"version":99,"active":0,"faces":[{"pips":["None"]}]}`, ErrEncodingVersion},
+		{"no faces", `{"version":1,"active":0,"faces":[]}`, ErrInvalidEncoding},
+		{"too many pips", `{"version":1,"active":0,"faces":[{"pips":["None","None","None","None","None","None","None","None","None","None"]}]}`, ErrInvalidEncoding},
+		{"no pips", `{"version":1,"active":0,"faces":[{"pips":[]}]}`, ErrInvalidEncoding},
+		{"unknown modifier", `{"version":1,"active":0,"faces":[{"pips":["Explode"]}]}`, ErrInvalidEncoding},
+		{"bad alt", `{"version":1,"active":0,"faces":[{"pips":["None"],"alts":[100]}]}`, ErrInvalidEncoding},
+		{"active out of range", `{"version":1,"active":3,"faces":[{"pips":["None"]}]}`, ErrInvalidEncoding},
+		{"too few weights", `{"version":2,"active":0,"faces":[{"pips":["None"]},{"pips":["None"]}],"weights":[1]}`, ErrInvalidEncoding},
+		{"negative weight", `{"version":2,"active":0,"faces":[{"pips":["None"]}],"weights":[-1]}`, ErrInvalidEncoding},
+		{"value out of range", `{"version":3,"active":0,"faces":[{"pips":["None"],"value":100}]}`, ErrInvalidEncoding},
+	}
+	for _, tc := range jsonTests {
+		t.Run("json "+tc.name, func(t *testing.T) {
+			var die Die
+			if err := json.Unmarshal([]byte(tc.data), &die); !errors.Is(err, tc.want) {
+				t.Errorf("Unmarshal() error = %v; want %v", err, tc.want)
+			}
+		})
+	}
+
+	binaryTests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrInvalidEncoding},
+		{"future version", []byte{9, 0, 1, 1, 0, 0}, ErrEncodingVersion},
+		{"cut short", []byte{ENCODING_VERSION, 0, 2, 1, 0, 0}, ErrInvalidEncoding},
+		{"unknown modifier", []byte{ENCODING_VERSION, 0, 1, 1, byte(NUM_MODIFIERS), 0, 1, 0}, ErrInvalidEncoding},
+		{"left over bytes", []byte{ENCODING_VERSION, 0, 1, 1, 0, 0, 1, 0, 7}, ErrInvalidEncoding},
+		{"weight cut short", []byte{ENCODING_VERSION, 0, 1, 1, 0, 0, 1, 1, 0x3f, 0x80}, ErrInvalidEncoding},
+		{"value out of range", []byte{ENCODING_VERSION, 0, 1, 1, 0, 0, 120, 0}, ErrInvalidEncoding},
+	}
+	for _, tc := range binaryTests {
+		t.Run("binary "+tc.name, func(t *testing.T) {
+			var die Die
+			if err := die.UnmarshalBinary(tc.data); !errors.Is(err, tc.want) {
+				t.Errorf("UnmarshalBinary() error = %v; want %v", err, tc.want)
+			}
+		})
+	}
+
+	for _, code := range []string{"", "hello", "DWR1-!!!", "DWR1-AQ", "XYZ1-AQA", "DWR1-AgA"} {
+		if _, err := DecodeDiceCode(code); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("DecodeDiceCode(%q) error = %v; want %v", code, err, ErrInvalidEncoding)
+		}
+	}
+	if _, err := DecodeDiceCode("DWR7-AQA"); !errors.Is(err, ErrEncodingVersion) {
+		t.Errorf("DecodeDiceCode(DWR7) error = %v; want %v", err, ErrEncodingVersion)
+	}
+}
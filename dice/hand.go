@@ -2,14 +2,15 @@ package dice
 
 import (
 	"slices"
-	"sort"
+	"strconv"
+	"strings"
 )
 
 // This pkg is used to determine hand outcome from die.Roll().Value()
 
+// the defaults every HandTable starts from. a run's mults live in its HandTable
 var (
 	//TODO:FIXME: could be a slice
-	//TODO: maybe will need player specific mult as it will change thru a run?
 	handRankMult = map[HandRank]float32{
 		NO_HAND:             0,
 		HIGH_DIE:            1,
@@ -23,14 +24,19 @@ var (
 		FOUR_OF_A_KIND:      5,
 		FIVE_OF_A_KIND:      7.5,
 		THREE_PAIR:          7.5,
+		STRAIGHT_PAIR:       8,
 		CROWDED_HOUSE:       10,
 		SIX_OF_A_KIND:       10,
 		STRAIGHT_LARGER:     12.5,
 		TWO_THREE_OF_A_KIND: 10,
 		OVERPOPULATED_HOUSE: 10,
+		STRAIGHT_TRIPS:      12.5,
 		STRAIGHT_LARGEST:    15,
 		FULLEST_HOUSE:       15,
 		SEVEN_OF_A_KIND:     17.5,
+		FOUR_PAIR:           18,
+		TWO_FOUR_OF_A_KIND:  20,
+		EIGHT_OF_A_KIND:     22.5,
 		SEVEN_SEVENS:        77,
 		STRAIGHT_MAX:        25,
 		UNKNOWN_HAND:        -1,
@@ -50,14 +56,19 @@ var (
 		FOUR_OF_A_KIND:      "Four of a Kind",
 		FIVE_OF_A_KIND:      "Five of a Kind",
 		THREE_PAIR:          "Three Pair",
+		STRAIGHT_PAIR:       "Paired Straight",
 		CROWDED_HOUSE:       "Crowded House",
 		SIX_OF_A_KIND:       "Six of a Kind",
 		STRAIGHT_LARGER:     "Large-r Straight",
 		TWO_THREE_OF_A_KIND: "Three's a Crowd",
 		OVERPOPULATED_HOUSE: "Overpopulated House",
+		STRAIGHT_TRIPS:      "Straight Trips",
 		STRAIGHT_LARGEST:    "Ultra Straight",
 		FULLEST_HOUSE:       "Fire Code Violation",
 		SEVEN_OF_A_KIND:     "Seven of a Kind",
+		FOUR_PAIR:           "Four Pair",
+		TWO_FOUR_OF_A_KIND:  "Double Decker",
+		EIGHT_OF_A_KIND:     "Eight of a Kind",
 		SEVEN_SEVENS:        "Lucky Sevens",
 		STRAIGHT_MAX:        "MEGA Straight",
 		UNKNOWN_HAND:        "UNKNOWN HAND (shouldn't see this)",
@@ -89,10 +100,11 @@ const (
 	FIVE_OF_A_KIND
 
 	// 6 die
-	// straight + pair?
 
 	// 2 + 2 + 2
 	THREE_PAIR
+	// small straight + a pair not in it
+	STRAIGHT_PAIR
 	// 4 + 2
 	CROWDED_HOUSE
 	SIX_OF_A_KIND
@@ -102,15 +114,25 @@ const (
 	TWO_THREE_OF_A_KIND
 
 	// 7 die
-	// straight + three of a kind?
 
 	// 4 + 3
 	OVERPOPULATED_HOUSE
+	// small straight + three of a kind not in it. pays like STRAIGHT_LARGER, it always outranks it
+	STRAIGHT_TRIPS
 	// 7 consecutive
 	STRAIGHT_LARGEST
 	// 5 + 2
 	FULLEST_HOUSE
 	SEVEN_OF_A_KIND
+
+	// 8+ die
+
+	// 2 + 2 + 2 + 2
+	FOUR_PAIR
+	// 4 + 4
+	TWO_FOUR_OF_A_KIND
+	EIGHT_OF_A_KIND
+
 	// 7 of a kind where all Value is 7
 	SEVEN_SEVENS
 
@@ -121,451 +143,541 @@ const (
 	UNKNOWN_HAND
 )
 
-// how much the rank multiplies to the total value of the hand
-func (h *HandRank) Multiplier() float32 {
-	return handRankMult[*h]
+// how much the rank multiplies to the total value of the hand for the run that owns table
+//
+// a nil table gives the default mult
+func (h *HandRank) Multiplier(table *HandTable) float32 {
+	if table == nil {
+		return handRankMult[*h]
+	}
+	return table.Multiplier(*h)
 }
 
 func (h *HandRank) String() string {
 	return handRankStringMap[*h]
 }
 
+// every straight HandRank, shortest first
+var StraightHands = []HandRank{STRAIGHT_SMALL, STRAIGHT_LARGE, STRAIGHT_LARGER, STRAIGHT_LARGEST, STRAIGHT_MAX}
+
+func (h *HandRank) IsStraight() bool {
+	return slices.Contains(StraightHands, *h)
+}
+
 // could be modified by gems?
 var (
 	STRAIGHT_SMALL_LENGTH   = 4
 	STRAIGHT_LARGE_LENGTH   = 5
 	STRAIGHT_LARGER_LENGTH  = 6
 	STRAIGHT_LARGEST_LENGTH = 7
+	STRAIGHT_MAX_LENGTH     = 8 // and anything longer
 
 	SNAKE_EYES_TARGET   = 1 // default to 1 bc obvious
 	SEVEN_SEVENS_TARGET = 7
 )
 
-// TODO: determine if this should return more info.
-// maybe meta-data/better dice info available?
-// don't want to enapsulate too much of the dice logic inside itself
-func trackUniqueValues(dice []Die) map[int][]Die {
-	tracker := map[int][]Die{}
+// StraightRules changes what counts as a straight. unlocked by gems or jewelry, kept on the run's HandTable
+//
+//	MinLength 3 -> [1, 2, 3] is a STRAIGHT_SMALL
+//	Gaps 1      -> [1, 2, 4, 5] is a STRAIGHT_SMALL, 3 is skipped
+//	Wrap        -> [8, 9, 1, 2] is a STRAIGHT_SMALL
+//
+// a straight's HandRank comes from how many dice are in it, skipped values don't count towards the length
+type StraightRules struct {
+	MinLength int  // the fewest dice that count as a STRAIGHT_SMALL. can only go down to 2
+	Gaps      int  // how many missing values one straight can skip over
+	Wrap      bool // MAX_PIPS is followed by 1
+}
 
-	for _, die := range dice {
-		x := die.ActiveFace().Value()
-		tracker[x] = append(tracker[x], die)
+// the StraightRules of a NewHandTable, and of every function that isn't given a HandTable
+func DefaultStraightRules() StraightRules {
+	return StraightRules{
+		MinLength: STRAIGHT_SMALL_LENGTH,
 	}
-
-	return tracker
 }
 
-// TODO:FIXME: this will need to be 100% sure it's the right die being passed
-// makes sure threepair is ACTUALLY THREE pairs
-func filterThreePair(dice []Die) []Die {
-	tracker := trackUniqueValues(dice)
+// never lets a straight be a single die
+func (r StraightRules) minLength() int {
+	return max(r.MinLength, 2)
+}
 
-	var collect []Die
-	for _, curValueDice := range tracker {
-		collect = append(collect, bestValues(curValueDice, 1)[:2]...) // top 2 hopefully
+// returns the HandRank for a straight made from length dice, NO_HAND if it is too short
+func (r StraightRules) rank(length int) HandRank {
+	switch {
+	case length < r.minLength():
+		return NO_HAND
+	case length <= STRAIGHT_SMALL_LENGTH: // shorter straights from MinLength are still small
+		return STRAIGHT_SMALL
+	case length == STRAIGHT_LARGE_LENGTH:
+		return STRAIGHT_LARGE
+	case length == STRAIGHT_LARGER_LENGTH:
+		return STRAIGHT_LARGER
+	case length == STRAIGHT_LARGEST_LENGTH:
+		return STRAIGHT_LARGEST
+	default: // STRAIGHT_MAX_LENGTH or longer
+		return STRAIGHT_MAX
 	}
+}
 
-	return collect
+// a value placed on a line of values. pos is where it sits when looking for a run,
+// wrapped values sit after MAX_PIPS again. 1 -> 10
+type linedValue struct {
+	value int
+	pos   int
 }
 
-// first check when determining HandRank
+// returns the values that make up the best straight under r, nil if there isn't one.
 //
-// returns NO_HAND if a straight is not found, otherwise returns the associated HandRank for the straight
-func checkStraight(values []int) HandRank {
-	// consecutive := [MAX_PIPS]bool{}
-
-	// for _, value := range values {
-	// 	consecutive[value] = true
-	// }
-
-	inARow := 1
-	var maxRow int
-	straight := NO_HAND
-	var lastValue, curValue int
-	slices.Sort(values)
-
-	// TODO: should this just input the dice and also return the sequence die? saves some loops
-	lastValue = values[0]
-	for i := 1; i < len(values); i += 1 {
-		curValue = values[i]
-
-		// TODO: modifiers for skip straight?
-
-		if lastValue+1 == curValue {
-			inARow += 1
-		} else {
-			maxRow = inARow
-			inARow = 1
+// the best straight is the longest, ties go to the highest values.
+// a wrapped straight is returned in order [8, 9, 1, 2]
+//
+//	values [1, 2, 3, 4, 6, 7] Gaps 1
+//	return [2, 3, 4, 6, 7]
+func (r StraightRules) find(values []int) []int {
+	unique := slices.Clone(values)
+	slices.Sort(unique)
+	unique = slices.Compact(unique)
+
+	line := make([]linedValue, len(unique))
+	for i, value := range unique {
+		line[i] = linedValue{value: value, pos: value}
+	}
+	best, bestSum := r.bestRun(line, len(line))
+
+	if r.Wrap { // only faces that can be rolled wrap, a 12 doesn't come before 1
+		var wrapLine []linedValue
+		for _, value := range unique {
+			if value >= 1 && value <= MAX_PIPS {
+				wrapLine = append(wrapLine, linedValue{value: value, pos: value})
+			}
+		}
+		starts := len(wrapLine)
+		for _, lined := range wrapLine[:starts] {
+			wrapLine = append(wrapLine, linedValue{value: lined.value, pos: lined.pos + MAX_PIPS})
 		}
 
-		lastValue = curValue
+		if wrapped, sum := r.bestRun(wrapLine, starts); len(wrapped) > len(best) || len(wrapped) == len(best) && sum > bestSum {
+			best = wrapped
+		}
 	}
 
-	// for value slices that are the entire sequence
-	if maxRow == 0 {
-		maxRow = inARow
-	}
+	return best
+}
+
+// returns the values of the longest run in line that starts in line[:starts], and the sum of its values.
+// a run never covers the same value twice
+func (r StraightRules) bestRun(line []linedValue, starts int) ([]int, int) {
+	var (
+		best    []int
+		bestSum int
+	)
+	for start := range starts {
+		gaps := 0
+		end := start
+		for end+1 < len(line) {
+			next := line[end+1]
+			if next.pos-line[start].pos >= MAX_PIPS && end+1 >= starts { // wrapped all the way around
+				break
+			}
+			skipped := next.pos - line[end].pos - 1
+			if gaps+skipped > r.Gaps {
+				break
+			}
+			gaps += skipped
+			end++
+		}
 
-	// for i := range consecutive {
-	// 	if consecutive[i] {
-	// 		curValue = i
-
-	// 		if lastValue+1 == curValue { // still works for 0 - 1
-	// 			inARow += 1
-	// 		} else if lastValue != curValue {
-	// 			inARow = 0
-	// 		}
-
-	// 		lastValue = curValue
-	// 	}
-	// }
-
-	if maxRow >= STRAIGHT_SMALL_LENGTH { // small straight
-		switch maxRow {
-		case STRAIGHT_SMALL_LENGTH:
-			straight = STRAIGHT_SMALL
-			// sortByValue()
-		case STRAIGHT_LARGE_LENGTH:
-			straight = STRAIGHT_LARGE
-		case STRAIGHT_LARGER_LENGTH:
-			straight = STRAIGHT_LARGER
-		case STRAIGHT_LARGEST_LENGTH:
-			straight = STRAIGHT_LARGEST
-		case STRAIGHT_LARGEST_LENGTH + 1: // for + 1 from modifiers??? FIXME: this shouldn't be hardcoded?
-			straight = STRAIGHT_MAX // placeholder TODO: STRAIGHT_MAX impl
+		length := end - start + 1
+		if length < r.minLength() {
+			continue
+		}
+
+		run := make([]int, 0, length)
+		sum := 0
+		for _, lined := range line[start : end+1] {
+			run = append(run, lined.value)
+			sum += lined.value
+		}
+		if length > len(best) || length == len(best) && sum > bestSum {
+			best = run
+			bestSum = sum
 		}
 	}
 
-	return straight
+	return best, bestSum
 }
 
-// second check when determining handRank
+// HandDefinition describes a HandRank that is made from groups of dice sharing a value.
 //
-//	// annoying logic for each one. comments are placed where it isn't obv after a glance
+//	FULL_HOUSE Groups {3, 2}
+//	THREE_PAIR Groups {2, 2, 2}
 //
-// returns NO_HAND if nothing is found, otherwise returns the highest HandRank that can be associated (other than straights)
-func checkHandOtherThanStraight(valueCount map[int]int, values []int, numDice int) HandRank {
-	uniqueValues := len(valueCount)
-	switch uniqueValues {
-	case 0:
-		return NO_HAND
-	case 1:
-		switch numDice {
-		case 1:
-			return HIGH_DIE
-		case 2:
-			if valueCount[SNAKE_EYES_TARGET] == numDice {
-				return SNAKE_EYES
-			} else {
-				return ONE_PAIR
-			}
-		case 3:
-			return THREE_OF_A_KIND
-		case 4:
-			return FOUR_OF_A_KIND
-		case 5:
-			return FIVE_OF_A_KIND
-		case 6:
-			return SIX_OF_A_KIND
-		case 7:
-			if valueCount[SEVEN_SEVENS_TARGET] == 7 {
-				return SEVEN_SEVENS
-			} else {
-				return SEVEN_OF_A_KIND
-			}
-		}
-	case 2:
-		switch numDice {
-		case 2: // 4 2
-			return HIGH_DIE
-		case 3: // 2 2 1
-			if valueCount[SNAKE_EYES_TARGET] == 2 {
-				return SNAKE_EYES
-			} else {
-				return ONE_PAIR
-			}
-		case 4: // 1 1 2 2, 1 1 1 2
-			if valueCount[values[0]] == 3 || valueCount[values[1]] == 3 {
-				return THREE_OF_A_KIND
-			}
-			return TWO_PAIR
-		case 5: // 1 2 2 2 2, 1 1 2 2 2
-			if valueCount[values[0]] == 2 && valueCount[values[1]] == 3 ||
-				valueCount[values[1]] == 2 && valueCount[values[0]] == 3 {
-				return FULL_HOUSE
-			} else {
-				return FOUR_OF_A_KIND
-			}
-		case 6: // 3 3 3 5 5 5, 1 1 2 2 2 2, 4 3 3 3 3 3,
-			if valueCount[values[0]] == valueCount[values[1]] {
-				return TWO_THREE_OF_A_KIND
-			} else if valueCount[values[0]] == 2 && valueCount[values[1]] == 4 ||
-				valueCount[values[1]] == 2 && valueCount[values[0]] == 4 {
-				return CROWDED_HOUSE
-			} else {
-				return FIVE_OF_A_KIND
-			}
-		case 7: // 1 1 1 2 2 2 2, 1 1 2 2 2 2 2, 1 2 2 2 2 2 2
-
-			if valueCount[values[0]] == 3 && valueCount[values[1]] == 4 ||
-				valueCount[values[1]] == 3 && valueCount[values[0]] == 4 {
-				return OVERPOPULATED_HOUSE
-			} else if valueCount[values[0]] == 2 && valueCount[values[1]] == 5 ||
-				valueCount[values[1]] == 2 && valueCount[values[0]] == 5 {
-				return FULLEST_HOUSE
-			} else {
-				return SIX_OF_A_KIND
-			}
+// a bigger group can fill a smaller one, 4 + 2 also has a FULL_HOUSE (CROWDED_HOUSE ranks higher though).
+// the highest HandRank that fits the dice's CountSignature wins, see checkHandOtherThanStraight
+type HandDefinition struct {
+	Rank   HandRank
+	Groups []int // how many dice each group of matching values needs, largest first
+	Target *int  // when not nil, the first group has to be this value. SNAKE_EYES, SEVEN_SEVENS
+}
+
+// every count based HandRank. straights are checked seperately, see checkStraight
+//
+// adding a hand is adding a line here (and its mult/name)
+var HandDefinitions = []HandDefinition{
+	{Rank: HIGH_DIE, Groups: []int{1}},
+	{Rank: ONE_PAIR, Groups: []int{2}},
+	{Rank: SNAKE_EYES, Groups: []int{2}, Target: &SNAKE_EYES_TARGET},
+	{Rank: TWO_PAIR, Groups: []int{2, 2}},
+	{Rank: THREE_OF_A_KIND, Groups: []int{3}},
+	{Rank: FULL_HOUSE, Groups: []int{3, 2}},
+	{Rank: FOUR_OF_A_KIND, Groups: []int{4}},
+	{Rank: FIVE_OF_A_KIND, Groups: []int{5}},
+	{Rank: THREE_PAIR, Groups: []int{2, 2, 2}},
+	{Rank: CROWDED_HOUSE, Groups: []int{4, 2}},
+	{Rank: SIX_OF_A_KIND, Groups: []int{6}},
+	{Rank: TWO_THREE_OF_A_KIND, Groups: []int{3, 3}},
+	{Rank: OVERPOPULATED_HOUSE, Groups: []int{4, 3}},
+	{Rank: FULLEST_HOUSE, Groups: []int{5, 2}},
+	{Rank: SEVEN_OF_A_KIND, Groups: []int{7}},
+	{Rank: FOUR_PAIR, Groups: []int{2, 2, 2, 2}},
+	{Rank: TWO_FOUR_OF_A_KIND, Groups: []int{4, 4}},
+	{Rank: EIGHT_OF_A_KIND, Groups: []int{8}},
+	{Rank: SEVEN_SEVENS, Groups: []int{7}, Target: &SEVEN_SEVENS_TARGET},
+}
+
+// CompoundHand is a straight plus a group of matching dice that aren't part of the straight.
+//
+// the group can share its value with the straight, it just needs its own dice
+//
+//	STRAIGHT_PAIR [1, 2, 3, 4, 3, 3] -> straight [1, 2, 3, 4] + pair [3, 3]
+//	              [1, 2, 3, 4, 3]    -> only a STRAIGHT_SMALL, the straight uses one of the 3s
+type CompoundHand struct {
+	Rank  HandRank
+	Group int // how many matching dice go with the straight
+}
+
+// every straight + group HandRank, checked alongside straights and HandDefinitions
+var CompoundHands = []CompoundHand{
+	{Rank: STRAIGHT_PAIR, Group: 2},
+	{Rank: STRAIGHT_TRIPS, Group: 3},
+}
+
+// returns the CompoundHand for hand, false if hand isn't a compound hand
+func compoundHand(hand HandRank) (CompoundHand, bool) {
+	for _, compound := range CompoundHands {
+		if compound.Rank == hand {
+			return compound, true
 		}
-	case 3:
-		switch numDice {
-		case 3:
-			return HIGH_DIE
-		case 4: // 1 1 2 3
-			if valueCount[SNAKE_EYES_TARGET] == 2 {
-				return SNAKE_EYES
-			} else {
-				return ONE_PAIR
-			}
-		case 5: // 1 1 1 2 3, 1 1 2 2 3
-			if valueCount[values[0]] == 3 ||
-				valueCount[values[1]] == 3 ||
-				valueCount[values[2]] == 3 {
-				return THREE_OF_A_KIND
-			} else {
-				return TWO_PAIR
-			}
-		case 6: // 1 1 1 1 2 3, 1 1 1 2 2 3, 1 1 2 2 3 3
-			if valueCount[values[0]] == 4 ||
-				valueCount[values[1]] == 4 ||
-				valueCount[values[2]] == 4 {
-				return FOUR_OF_A_KIND
-			} else if valueCount[values[0]] == valueCount[values[1]] && valueCount[values[1]] == valueCount[values[2]] {
-				return THREE_PAIR
-			} else {
-				return FULL_HOUSE
-			}
-		case 7: // 1 1 1 1 1 2 3, 1 1 1 1 2 2 3, 1 1 1 2 2 2 3
-			if valueCount[values[0]] == 5 ||
-				valueCount[values[1]] == 5 ||
-				valueCount[values[2]] == 5 {
-				return FIVE_OF_A_KIND
-			} else if valueCount[values[0]] == 3 && valueCount[values[1]] == 3 ||
-				valueCount[values[0]] == 3 && valueCount[values[2]] == 3 ||
-				valueCount[values[1]] == 3 && valueCount[values[2]] == 3 {
-				return TWO_THREE_OF_A_KIND
-			} else if (valueCount[values[0]] == 2 && valueCount[values[1]] == 4 ||
-				valueCount[values[1]] == 2 && valueCount[values[0]] == 4) ||
-
-				(valueCount[values[1]] == 2 && valueCount[values[2]] == 4 ||
-					valueCount[values[2]] == 2 && valueCount[values[1]] == 4) ||
-
-				(valueCount[values[0]] == 2 && valueCount[values[2]] == 4 ||
-					valueCount[values[2]] == 2 && valueCount[values[0]] == 4) {
-				return CROWDED_HOUSE
-			} else {
-				// values have to be 1, 1, 2, 2, 3, 3, 3
-				// if valueCount[0] == 2 && valueCount[1] == 2 ||
-				// 	valueCount[1] == 2 || valueCount[2] == 2 {
-
-				// }
-				return THREE_PAIR
-			}
+	}
+	return CompoundHand{}, false
+}
+
+// returns the values of the best straight and the value of the group that make up c, false if they can't be made.
+//
+// the longest straight wins, ties go to the highest group value
+func (c CompoundHand) find(valueCount map[int]int, rules StraightRules) ([]int, int, bool) {
+	var (
+		best      []int
+		bestGroup int
+		found     bool
+	)
+	for group, count := range valueCount {
+		if count < c.Group {
+			continue
 		}
-	case 4:
-		switch numDice {
-		case 4:
-			return HIGH_DIE
-		case 5: // 1 1 2 3 4
-			if valueCount[SNAKE_EYES_TARGET] == 2 {
-				return SNAKE_EYES
-			} else {
-				return ONE_PAIR
-			}
-		case 6: // 1 1 1 2 3 4, 1 1 2 2 3 4
-			if valueCount[values[0]] == 3 ||
-				valueCount[values[1]] == 3 ||
-				valueCount[values[2]] == 3 ||
-				valueCount[values[3]] == 3 {
-				return THREE_OF_A_KIND
-			} else {
-				return TWO_PAIR
-			}
-		case 7: // 1 1 1 1 2 3 4, 1 1 1 2 2 3 4, 1 1 2 2 3 3 4
-			if valueCount[values[0]] == 4 ||
-				valueCount[values[1]] == 4 ||
-				valueCount[values[2]] == 4 ||
-				valueCount[values[3]] == 4 {
-				return FOUR_OF_A_KIND
-			} else if (valueCount[values[0]] == valueCount[values[1]] && valueCount[values[0]] == valueCount[values[2]]) || // 0 = 1 & 2
-				(valueCount[values[0]] == valueCount[values[2]] && valueCount[values[0]] == valueCount[values[3]]) || // 0 = 2 & 3
-				(valueCount[values[0]] == valueCount[values[1]] && valueCount[values[0]] == valueCount[values[3]]) || // 0 = 1 & 3
-				(valueCount[values[1]] == valueCount[values[2]] && valueCount[values[1]] == valueCount[values[3]]) { // 1 = 2 & 3
-
-				//  else if (valueCount[values[0]] == valueCount[values[1]] && valueCount[values[1]] == valueCount[values[2]]) ||
-				// 	(valueCount[values[1]] == valueCount[values[2]] && valueCount[values[2]] == valueCount[values[3]]) {
-				return THREE_PAIR
-
-			} else {
-				return FULL_HOUSE
+
+		// the straight can only have group's value when there is a die left over for it
+		var left []int
+		for value, count := range valueCount {
+			if value != group || count > c.Group {
+				left = append(left, value)
 			}
 		}
-	case 5:
-		switch numDice {
-		case 5:
-			return HIGH_DIE
-		case 6: // 1 1 2 3 4 5
-			if valueCount[SNAKE_EYES_TARGET] == 2 {
-				return SNAKE_EYES
-			} else {
-				return ONE_PAIR
-			}
-		case 7: // 1 1 1 2 3 4 5, 1 1 2 2 3 4 5
-			if valueCount[values[0]] == 3 ||
-				valueCount[values[1]] == 3 ||
-				valueCount[values[2]] == 3 ||
-				valueCount[values[3]] == 3 ||
-				valueCount[values[4]] == 3 {
-				return THREE_OF_A_KIND
-			} else {
-				return TWO_PAIR
-			}
+
+		straight := rules.find(left)
+		if straight == nil {
+			continue
 		}
-	case 6:
-		switch numDice {
-		case 6:
-			return HIGH_DIE
-		case 7:
-			if valueCount[SNAKE_EYES_TARGET] == 2 {
-				return SNAKE_EYES
-			} else {
-				return ONE_PAIR
-			}
+		if !found || len(straight) > len(best) || len(straight) == len(best) && group > bestGroup {
+			best, bestGroup, found = straight, group, true
 		}
-	case 7:
-		return HIGH_DIE
 	}
+	return best, bestGroup, found
+}
 
-	return UNKNOWN_HAND // SHOULD NOT GET HERE!
+// returns the highest CompoundHand rank found in valueCount, NO_HAND if there isn't one
+func checkCompound(valueCount map[int]int, rules StraightRules) HandRank {
+	handFound := NO_HAND
+	for _, compound := range CompoundHands {
+		if compound.Rank <= handFound {
+			continue
+		}
+		if _, _, ok := compound.find(valueCount, rules); ok {
+			handFound = compound.Rank
+		}
+	}
+	return handFound
 }
 
-// TOP LEVEL DIE IMPLEMENTATION IN /die.go
+// returns the indexes of values that make up c, the group first then the straight. nil if c can't be made
 //
-// returns straight with the BEST values for the conesecutive.
+//	values [1, 2, 3, 4, 3, 3] STRAIGHT_PAIR
+//	return [2, 4, 5, 0, 1, 3] // group [3, 3] + straight [1, 2, 3, 4]
+func findCompoundIndices(c CompoundHand, values []int, own []int, rules StraightRules) []int {
+	tracker := trackUniqueValues(values)
+	counts := map[int]int{}
+	for value, indices := range tracker {
+		counts[value] = len(indices)
+	}
+
+	straight, group, ok := c.find(counts, rules)
+	if !ok {
+		return nil
+	}
+
+	found := bestValues(tracker[group], own, c.Group)
+	for _, value := range straight {
+		left := slices.DeleteFunc(slices.Clone(tracker[value]), func(i int) bool {
+			return slices.Contains(found, i)
+		})
+		found = append(found, bestValues(left, own, 1)...)
+	}
+	return found
+}
+
+// returns the HandDefinition for hand, false if hand isn't count based
+func handDefinition(hand HandRank) (HandDefinition, bool) {
+	for _, def := range HandDefinitions {
+		if def.Rank == hand {
+			return def, true
+		}
+	}
+	return HandDefinition{}, false
+}
+
+// CountSignature is how many dice share each value, largest first.
 //
-// # for modifiers, etc the tie breaker is ALWAYS the true number of .pips on the die.
+//	values [3, 3, 3, 5, 5, 1] -> 3+2+1
+type CountSignature []int
+
+func (c CountSignature) String() string {
+	var sb strings.Builder
+	for i, count := range c {
+		if i > 0 {
+			sb.WriteString("+")
+		}
+		sb.WriteString(strconv.Itoa(count))
+	}
+	return sb.String()
+}
+
+// true when each group can be made out of a different value.
+// the largest count fills the largest group and so on
 //
-// The dice given MUST be a straight
-func findBestSingleConsecutive(dice []Die) []Die {
-	tracker := trackUniqueValues(dice)
-
-	// trackedLen := len(tracker)
-
-	// if trackedLen < STRAIGHT_SMALL_LENGTH { // this check might not be needed bc dice WILL be straights when they get here
-	// 	return []Die{} // explicitly empty
-	// }
-
-	// going from the top gets best straight - but idk how to do it smartly
-	// dont love this. could be found in trackUniqueValues but would be wasted elsewhere?
-	var topValue int
-	for value := range tracker {
-		if value > topValue {
-			topValue = value
+//	3+2+1 fits {3, 2}, {2, 2, 1}
+//	3+2+1 does not fit {4}, {2, 2, 2}
+func (c CountSignature) Fits(groups []int) bool {
+	if len(groups) > len(c) {
+		return false
+	}
+	for i, need := range groups {
+		if c[i] < need {
+			return false
 		}
 	}
+	return true
+}
 
-	var inARow int = 1
-	for i := topValue; i > 0; i -= 1 {
-		if len(tracker[i-1]) > 0 { // if there is dice below our current Value, 1 more in a row=
-			inARow += 1
-			if i > topValue {
-				topValue = i - 1
-			}
-		} else {
-			if inARow < STRAIGHT_SMALL_LENGTH {
-				topValue = 0 // set to 0 for topval find
-				inARow = 0   // resets to 0. when going from a blank index it'll still add 1, so it's 0 to 1 in a row from a new found number
-			} else {
-				// could assume that there will never be a sequence once one is found
-				// TODO: would not work with 1-3 straight or more than 7 dice
-				break
-			}
+// a value and how many dice have it
+type valueGroup struct {
+	value int
+	count int
+}
+
+// returns every value's group, largest count first. ties go to the highest value
+func groupValues(valueCount map[int]int) []valueGroup {
+	groups := make([]valueGroup, 0, len(valueCount))
+	for value, count := range valueCount {
+		groups = append(groups, valueGroup{value: value, count: count})
+	}
+	slices.SortFunc(groups, func(a, b valueGroup) int {
+		if a.count != b.count {
+			return b.count - a.count
 		}
+		return b.value - a.value
+	})
+	return groups
+}
+
+// returns the CountSignature of valueCount
+func countSignature(valueCount map[int]int) CountSignature {
+	groups := groupValues(valueCount)
+	signature := make(CountSignature, len(groups))
+	for i, group := range groups {
+		signature[i] = group.count
 	}
+	return signature
+}
 
-	var sequenceDice []Die
-	for i := topValue - inARow + 1; i <= topValue; i++ {
-		var die Die
-		if len(tracker[i]) > 1 {
-			die = bestValues(tracker[i], 1)[0]
-		} else {
-			die = tracker[i][0]
+// finds a distinct value for each of def.Groups that has enough dice, preferring the highest values.
+//
+// returns the value picked for each group, false if def does not fit
+func matchGroups(groups []valueGroup, def HandDefinition) ([]int, bool) {
+	var (
+		used    = make([]bool, len(groups))
+		picked  = make([]int, len(def.Groups))
+		best    []int
+		bestSum int
+	)
+
+	var pick func(g, sum int)
+	pick = func(g, sum int) {
+		if g == len(def.Groups) {
+			if best == nil || sum > bestSum {
+				best = slices.Clone(picked)
+				bestSum = sum
+			}
+			return
+		}
+		for i, group := range groups {
+			if used[i] || group.count < def.Groups[g] {
+				continue
+			}
+			if g == 0 && def.Target != nil && group.value != *def.Target {
+				continue
+			}
+			used[i] = true
+			picked[g] = group.value
+			pick(g+1, sum+group.value*def.Groups[g])
+			used[i] = false
 		}
-		sequenceDice = append(sequenceDice, die)
 	}
+	pick(0, 0)
 
-	// probably the most innefficient way to check for straights.
-	//TODO: make this better. it just stinks
-	return sequenceDice
+	return best, best != nil
 }
 
-// TOP LEVEL DIE IMPLEMENTATION IN die.go
+// TODO: determine if this should return more info.
+// maybe meta-data/better dice info available?
+// don't want to enapsulate too much of the dice logic inside itself
 //
-// TODO: make this more efficient
+// returns the indexes of values grouped by value
+func trackUniqueValues(values []int) map[int][]int {
+	tracker := map[int][]int{}
+
+	for i, x := range values {
+		tracker[x] = append(tracker[x], i)
+	}
+
+	return tracker
+}
+
+// returns the indexes of values that make up def, only as many dice as each group needs.
 //
-// returns slice of Die from input die that share valuess.
+// dice that share a value are picked by their own Value(), a real 5 before a wild counting as 5
 //
-//	dice values [1, 2, 1, 3, 4, 2]
-//	return pairs [1, 1, 2, 2] // order not guaranteed
+//	values [3, 3, 3, 5, 5, 5, 1] FULL_HOUSE
+//	return [3, 4, 5, 0, 1] // [5, 5, 5, 3, 3]
+func findGroupIndices(def HandDefinition, values []int, own []int) []int {
+	tracker := trackUniqueValues(values)
+	counts := map[int]int{}
+	for value, indices := range tracker {
+		counts[value] = len(indices)
+	}
+
+	picked, ok := matchGroups(groupValues(counts), def)
+	if !ok {
+		return nil
+	}
+
+	var found []int
+	for g, value := range picked {
+		found = append(found, bestValues(tracker[value], own, def.Groups[g])...)
+	}
+	return found
+}
+
+// first check when determining HandRank
 //
-//	dice := [1, 2, 2, 2, 3]
-//	return [2, 2, 2]
+// what counts as a straight comes from rules, see StraightRules
 //
-//	dice [1, 3, 2, 2, 2, 3]
-//	return [3, 3, 2, 2, 2]
-func findMatchingValues(dice []Die) []Die {
-	tracker := trackUniqueValues(dice)
+// returns NO_HAND if a straight is not found, otherwise returns the associated HandRank for the straight
+func checkStraight(values []int, rules StraightRules) HandRank {
+	return rules.rank(len(rules.find(values)))
+}
 
-	var matchingValues []Die
+// second check when determining handRank
+//
+// compares the CountSignature of the values against every HandDefinitions
+//
+// returns NO_HAND if nothing is found, otherwise returns the highest HandRank that can be associated (other than straights)
+func checkHandOtherThanStraight(valueCount map[int]int, values []int, numDice int) HandRank {
+	if numDice == 0 || len(valueCount) == 0 {
+		return NO_HAND
+	}
 
-	for _, diceThisValue := range tracker {
-		if len(diceThisValue) > 1 {
-			matchingValues = append(matchingValues, diceThisValue...)
+	groups := groupValues(valueCount)
+	signature := countSignature(valueCount)
+	handFound := UNKNOWN_HAND // SHOULD NOT STAY THIS! HIGH_DIE fits any die
+	for _, def := range HandDefinitions {
+		if handFound != UNKNOWN_HAND && def.Rank <= handFound {
+			continue
+		}
+		if !signature.Fits(def.Groups) {
+			continue
+		}
+		if def.Target != nil { // the signature doesn't know the values
+			if _, ok := matchGroups(groups, def); !ok {
+				continue
+			}
 		}
+		handFound = def.Rank
 	}
-	return matchingValues
+
+	return handFound
 }
 
-// TODO: clean this up. refactor etc
+// returns straight with the BEST values for the conesecutive.
 //
-// returns the X values with the most .Value() of input die.
+// # for modifiers, etc the tie breaker is ALWAYS the face's own .Value(), never its pips.
 //
-//	dice [1, 1, 2, 2] x = 1
-//	return [2, 2]
-//	dice [1, 1, 2, 2, 2, 3, 3] x = 2
-//	return [2, 2, 2, 3, 3] // order not guaranteed
-func bestValues(dice []Die, x int) []Die {
-	var uniqueValues []int
-	var bestValues []Die
-
-	for _, die := range dice {
-		pips := die.ActiveFace().NumPips()
-		if !slices.Contains(uniqueValues, pips) {
-			uniqueValues = append(uniqueValues, pips)
-		}
+// The dice given MUST be a straight under rules
+func findBestSingleConsecutive(dice []Die, rules StraightRules) []Die {
+	values := make([]int, len(dice))
+	for i := range dice {
+		values[i] = dice[i].ActiveFace().Value()
 	}
 
-	sort.Slice(uniqueValues, func(i, j int) bool {
-		return uniqueValues[i] < uniqueValues[j]
-	})
+	var sequenceDice []Die
+	for _, i := range FindStraightIndices(values, faceValues(activeFaces(dieRefs(dice))), rules) {
+		sequenceDice = append(sequenceDice, dice[i])
+	}
+	return sequenceDice
+}
 
-	uniqueValues = uniqueValues[:x] // 0 - x exclusive
+// FindStraightIndices returns the indexes of values that make up the best straight under rules,
+// one index per value. nil if there is no straight
+//
+// own is each face's own Value(), the tie breaker for dice that count as the same value
+func FindStraightIndices(values []int, own []int, rules StraightRules) []int {
+	tracker := trackUniqueValues(values)
 
-	for _, die := range dice {
-		if slices.Contains(uniqueValues, die.ActiveFace().NumPips()) { // TODO: figure out if this should be numpip or value
-			bestValues = append(bestValues, die)
-		}
+	var sequence []int
+	for _, value := range rules.find(values) {
+		sequence = append(sequence, bestValues(tracker[value], own, 1)...)
 	}
+	return sequence
+}
+
+// returns the x indexes with the highest own value, the tie breaker for dice that share a value.
+//
+//	indices [0, 1, 2] own [2, 5, 2] x = 1
+//	return [1]
+func bestValues(indices []int, own []int, x int) []int {
+	sorted := slices.Clone(indices)
+	slices.SortStableFunc(sorted, func(a, b int) int {
+		return own[b] - own[a]
+	})
 
-	return bestValues
+	return sorted[:min(x, len(sorted))]
 }
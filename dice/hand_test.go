@@ -116,7 +116,7 @@ func compareDiceSlicesUnordered(t *testing.T, gotDice []Die, wantValues []int, t
 		expectedLen = 5
 	case SIX_OF_A_KIND, STRAIGHT_LARGER, TWO_THREE_OF_A_KIND, CROWDED_HOUSE, THREE_PAIR:
 		expectedLen = 6
-	case OVERPOPULATED_HOUSE, FULLEST_HOUSE, SEVEN_OF_A_KIND, SEVEN_SEVENS:
+	case OVERPOPULATED_HOUSE, FULLEST_HOUSE, SEVEN_OF_A_KIND, SEVEN_SEVENS: // compound hands are as long as their straight
 		expectedLen = 7
 		// Add STRAIGHT_LARGEST, STRAIGHT_MAX if needed
 	}
@@ -241,7 +241,7 @@ func TestStraightDetection(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := checkStraight(tc.values)
+			got := checkStraight(tc.values, DefaultStraightRules())
 			if got != tc.expected {
 				t.Errorf("checkStraight(%v) = %s; want %s",
 					tc.values, got.String(), tc.expected.String())
@@ -259,15 +259,15 @@ func TestComplexHandInteractions(t *testing.T) {
 		expected   HandRank
 	}{
 		// Cases where both straight and other patterns exist together
-		{"STRAIGHT_SMALL beats THREE_OF_A_KIND",
-			[]int{1, 2, 3, 4, 3, 3}, 6, STRAIGHT_SMALL},
+		{"STRAIGHT_PAIR beats THREE_OF_A_KIND",
+			[]int{1, 2, 3, 4, 3, 3}, 6, STRAIGHT_PAIR},
 
-		{"STRAIGHT_SMALL beats THREE_OF_A_KIND",
-			[]int{1, 2, 3, 4, 4, 4}, 6, STRAIGHT_SMALL},
+		{"STRAIGHT_PAIR beats THREE_OF_A_KIND",
+			[]int{1, 2, 3, 4, 4, 4}, 6, STRAIGHT_PAIR},
 
 		// Precedence between straight types
-		{"STRAIGHT_LARGE wins over STRAIGHT_SMALL",
-			[]int{1, 2, 3, 4, 5, 1, 2}, 6, STRAIGHT_LARGE},
+		{"STRAIGHT_PAIR wins over STRAIGHT_LARGE",
+			[]int{1, 2, 3, 4, 5, 1, 2}, 6, STRAIGHT_PAIR},
 
 		{"STRAIGHT_LARGER wins over STRAIGHT_LARGE",
 			[]int{1, 2, 3, 4, 5, 6, 1}, 6, STRAIGHT_LARGER},
@@ -276,11 +276,11 @@ func TestComplexHandInteractions(t *testing.T) {
 			[]int{1, 2, 3, 4, 5, 6, 7}, 7, STRAIGHT_LARGEST},
 
 		// Special cases with straights
-		{"STRAIGHT_LARGE with duplicate values",
-			[]int{1, 2, 3, 4, 5, 5, 5}, 6, STRAIGHT_LARGE},
+		{"STRAIGHT_TRIPS from duplicate values",
+			[]int{1, 2, 3, 4, 5, 5, 5}, 6, STRAIGHT_TRIPS},
 
-		{"STRAIGHT_SMALL with higher duplicates",
-			[]int{1, 2, 3, 4, 6, 6, 6}, 6, STRAIGHT_SMALL},
+		{"STRAIGHT_TRIPS with higher duplicates",
+			[]int{1, 2, 3, 4, 6, 6, 6}, 6, STRAIGHT_TRIPS},
 
 		// Edge cases
 		{"Discontinuous not a straight", []int{1, 2, 3, 5, 6, 7}, 7, HIGH_DIE},
@@ -296,7 +296,7 @@ func TestComplexHandInteractions(t *testing.T) {
 					tc.diceValues, got.String(), tc.expected.String())
 
 				// Debug output to help diagnose issues
-				straightResult := checkStraight(tc.diceValues)
+				straightResult := checkStraight(tc.diceValues, DefaultStraightRules())
 				valueCount := map[int]int{}
 				for _, val := range tc.diceValues {
 					valueCount[val]++
@@ -322,11 +322,11 @@ func TestStraightVsOtherPatterns(t *testing.T) {
 		maxPips    int
 		expected   HandRank
 	}{
-		{"FOUR_OF_A_KIND beats FOUR_OF_A_KIND",
-			[]int{1, 2, 3, 4, 2, 2, 2}, 6, FOUR_OF_A_KIND},
+		{"STRAIGHT_TRIPS beats FOUR_OF_A_KIND",
+			[]int{1, 2, 3, 4, 2, 2, 2}, 6, STRAIGHT_TRIPS},
 
-		{"STRAIGHT_SMALL beats THREE_OF_A_KIND",
-			[]int{3, 3, 3, 1, 2, 4}, 6, STRAIGHT_SMALL},
+		{"STRAIGHT_PAIR beats THREE_OF_A_KIND",
+			[]int{3, 3, 3, 1, 2, 4}, 6, STRAIGHT_PAIR},
 
 		{"STRAIGHT_LARGER beats THREE_OF_A_KIND",
 			[]int{1, 2, 3, 4, 5, 6, 6}, 6, STRAIGHT_LARGER},
@@ -368,7 +368,7 @@ func TestSevenDiceHands(t *testing.T) {
 		{"CROWDED_HOUSE (4+2+1)", []int{4, 4, 4, 4, 5, 5, 6}, 6, CROWDED_HOUSE},
 		{"TWO_THREE_OF_A_KIND (3+3+1)", []int{2, 2, 2, 5, 5, 5, 6}, 6, TWO_THREE_OF_A_KIND},
 		{"THREE_PAIR with single (2+2+2+1)", []int{1, 1, 3, 3, 5, 5, 6}, 6, THREE_PAIR},
-		{"STRAIGHT_SMALL beats FOUR_OF_A_KIND", []int{1, 2, 3, 4, 4, 4, 4}, 6, FOUR_OF_A_KIND},
+		{"STRAIGHT_TRIPS beats FOUR_OF_A_KIND", []int{1, 2, 3, 4, 4, 4, 4}, 6, STRAIGHT_TRIPS},
 		{"FULL_HOUSE with extras", []int{5, 5, 5, 6, 6, 1, 2}, 6, FULL_HOUSE},
 		{"STRAIGHT_LARGER with pair", []int{1, 2, 3, 4, 5, 6, 6}, 6, STRAIGHT_LARGER},
 		{"STRAIGHT_LARGER with different dice", []int{1, 2, 3, 4, 5, 6, 1}, 6, STRAIGHT_LARGER},
@@ -493,7 +493,7 @@ func debugHandAnalysis(t *testing.T, values []int) {
 	t.Logf("Value counts: %v", valueCount)
 
 	// Check for straights
-	straightResult := checkStraight(values)
+	straightResult := checkStraight(values, DefaultStraightRules())
 	t.Logf("Straight check result: %s", straightResult.String())
 
 	// Check for other hands
@@ -532,11 +532,11 @@ func TestFindHandRankDice_MatchingValues(t *testing.T) {
 		{"SEVEN_SEVENS special", SEVEN_SEVENS, []int{7, 7, 7, 7, 7, 7, 7}, 7, []int{7, 7, 7, 7, 7, 7, 7}},
 
 		// --- Multiple Group Matches ---
-		// findMatchingValues should return *all* dice involved in these specific hands.
+		// FindHandRankDice should return *all* dice involved in these specific hands.
 		{"TWO_PAIR", TWO_PAIR, []int{2, 2, 4, 4, 5}, 6, []int{2, 2, 4, 4}},
 		{"THREE_PAIR", THREE_PAIR, []int{1, 1, 3, 3, 5, 5, 6}, 6, []int{1, 1, 3, 3, 5, 5}},
 
-		// Full House: Uses findMatchingValues then bestValues(..., 2).
+		// Full House: picks the 3 group then the 2 group from HandDefinitions.
 		// Assuming bestValues correctly identifies the two groups (or doesn't incorrectly filter them),
 		// it should return the dice comprising the 3-of-a-kind and the pair.
 		{"FULL_HOUSE (3+2)", FULL_HOUSE, []int{3, 3, 3, 5, 5}, 6, []int{3, 3, 3, 5, 5}},
@@ -580,7 +580,7 @@ func TestFindHandRankDice_MatchingValues(t *testing.T) {
 			}
 
 			// Call the function under test with the *correct* hand rank
-			foundDice := FindHandRankDice(tc.inputHandRank, dice)
+			foundDice := FindHandRankDice(tc.inputHandRank, dice, nil)
 
 			// Compare the values of the returned dice (order-independent)
 			compareDiceSlicesUnordered(t, foundDice, tc.expectedDiceValues, tc.name, tc.inputDiceValues, tc.inputHandRank)
@@ -637,7 +637,7 @@ func TestFindBestSingleConsecutive(t *testing.T) {
 			// fmt.Println("Input Dice Values:", tc.inputDiceValues)
 
 			// Call the specific function under test
-			foundDice := findBestSingleConsecutive(dice)
+			foundDice := findBestSingleConsecutive(dice, DefaultStraightRules())
 
 			// fmt.Println("Expected Values:", tc.expectedDiceValues)
 			// fmt.Println("Found Dice Values:", extractDiceValues(foundDice))
@@ -677,3 +677,329 @@ func compareStraightResult(t *testing.T, gotDice []Die, wantValues []int, testNa
 			testName, inputValues, gotValues, wantValues)
 	}
 }
+
+// makes a die that is always showing face
+func dieShowing(face Face) Die {
+	return Die{faces: []Face{face}}
+}
+
+// Test for wild (ModWILD) and split ("2 or 5") faces resolving to the best HandRank
+func TestWildFaces(t *testing.T) {
+	wild := NewModFace(ModWILD)
+	tests := []struct {
+		name       string
+		dice       []Die
+		expected   HandRank
+		wantValues []int // values of the dice from FindHandRankValues
+	}{
+		{"wild makes a pair", []Die{dieShowing(MustFace(3)), dieShowing(wild)}, ONE_PAIR, []int{3, 3}},
+		{"wild pairs with the highest die", []Die{dieShowing(MustFace(3)), dieShowing(MustFace(5)), dieShowing(wild)}, ONE_PAIR, []int{5, 5}},
+		{"wild completes a straight", []Die{dieShowing(MustFace(2)), dieShowing(MustFace(3)), dieShowing(wild), dieShowing(MustFace(5)), dieShowing(MustFace(6))}, STRAIGHT_LARGE, []int{2, 3, 4, 5, 6}},
+		{"wild completes a full house", []Die{dieShowing(MustFace(2)), dieShowing(MustFace(2)), dieShowing(MustFace(5)), dieShowing(MustFace(5)), dieShowing(wild)}, FULL_HOUSE, []int{2, 2, 5, 5, 5}},
+		{"two wilds make five of a kind", []Die{dieShowing(MustFace(4)), dieShowing(MustFace(4)), dieShowing(MustFace(4)), dieShowing(wild), dieShowing(wild)}, FIVE_OF_A_KIND, []int{4, 4, 4, 4, 4}},
+		{"split counts as its alternate", []Die{dieShowing(MustFace(5)), dieShowing(MustSplitFace(2, 5))}, ONE_PAIR, []int{5, 5}},
+		{"split counts as its pips", []Die{dieShowing(MustFace(2)), dieShowing(MustFace(2)), dieShowing(MustSplitFace(2, 5))}, THREE_OF_A_KIND, []int{2, 2, 2}},
+		{"split counts as an alternate past MAX_PIPS", []Die{dieShowing(MustValueFace(2, 20)), dieShowing(MustSplitFace(2, 20))}, ONE_PAIR, []int{20, 20}},
+		{"split and wild together", []Die{dieShowing(MustFace(1)), dieShowing(MustSplitFace(4, 2)), dieShowing(wild), dieShowing(MustFace(4))}, STRAIGHT_SMALL, []int{1, 2, 3, 4}},
+		{"wild completes a straight past MAX_PIPS", []Die{dieShowing(MustValueFace(2, 20)), dieShowing(MustValueFace(1, 19)), dieShowing(MustValueFace(9, 18)), dieShowing(wild)}, STRAIGHT_SMALL, []int{18, 19, 20, 21}},
+		{"wild completes a straight below 1", []Die{dieShowing(MustValueFace(1, 0)), dieShowing(MustValueFace(1, -1)), dieShowing(MustFace(2)), dieShowing(wild)}, STRAIGHT_SMALL, []int{-1, 0, 1, 2}},
+		{"wilds make four pair over fullest house", []Die{dieShowing(MustFace(1)), dieShowing(MustFace(1)), dieShowing(MustFace(2)), dieShowing(MustFace(2)), dieShowing(MustFace(3)), dieShowing(wild), dieShowing(wild), dieShowing(wild)}, FOUR_PAIR, []int{1, 1, 2, 2, 3, 3, 10, 10}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DetermineHandRank(tc.dice)
+			if got != tc.expected {
+				t.Fatalf("DetermineHandRank() = %s; want %s", got.String(), tc.expected.String())
+			}
+
+			_, values := FindHandRankValues(got, tc.dice, nil)
+			sort.Ints(values)
+			if !reflect.DeepEqual(values, tc.wantValues) {
+				t.Errorf("FindHandRankValues(%s) values = %v; want %v", got.String(), values, tc.wantValues)
+			}
+		})
+	}
+}
+
+// Test for dice pools bigger than 7, the hands have to come from the HandDefinitions table
+func TestBigDicePools(t *testing.T) {
+	tests := []struct {
+		name       string
+		diceValues []int
+		maxPips    int
+		expected   HandRank
+		wantValues []int // values of the dice from FindHandRankDice
+	}{
+		{"EIGHT_OF_A_KIND", []int{4, 4, 4, 4, 4, 4, 4, 4}, 6, EIGHT_OF_A_KIND, []int{4, 4, 4, 4, 4, 4, 4, 4}},
+		{"TWO_FOUR_OF_A_KIND (4+4)", []int{2, 2, 2, 2, 6, 6, 6, 6}, 6, TWO_FOUR_OF_A_KIND, []int{2, 2, 2, 2, 6, 6, 6, 6}},
+		{"FOUR_PAIR (2+2+2+2)", []int{1, 1, 3, 3, 5, 5, 6, 6}, 6, FOUR_PAIR, []int{1, 1, 3, 3, 5, 5, 6, 6}},
+		{"SEVEN_SEVENS with an extra", []int{7, 7, 7, 7, 7, 7, 7, 2}, 7, SEVEN_SEVENS, []int{7, 7, 7, 7, 7, 7, 7}},
+		{"SEVEN_OF_A_KIND beats FOUR_PAIR", []int{3, 3, 3, 3, 3, 3, 3, 1}, 6, SEVEN_OF_A_KIND, []int{3, 3, 3, 3, 3, 3, 3}},
+		{"FULLEST_HOUSE out of 5+3", []int{2, 2, 2, 2, 2, 5, 5, 5}, 6, FULLEST_HOUSE, []int{2, 2, 2, 2, 2, 5, 5}},
+		{"OVERPOPULATED_HOUSE picks the highest pair", []int{1, 1, 1, 1, 3, 3, 3, 6, 6}, 6, OVERPOPULATED_HOUSE, []int{1, 1, 1, 1, 3, 3, 3}},
+		{"FOUR_PAIR out of 5 pairs", []int{1, 1, 2, 2, 3, 3, 5, 5, 6, 6}, 6, FOUR_PAIR, []int{2, 2, 3, 3, 5, 5, 6, 6}},
+		{"TWO_FOUR_OF_A_KIND out of 4+4+4", []int{1, 1, 1, 1, 4, 4, 4, 4, 6, 6, 6, 6}, 6, TWO_FOUR_OF_A_KIND, []int{4, 4, 4, 4, 6, 6, 6, 6}},
+		{"EIGHT_OF_A_KIND in 12 dice", []int{9, 9, 9, 9, 9, 9, 9, 9, 9, 1, 1, 1}, 9, EIGHT_OF_A_KIND, []int{9, 9, 9, 9, 9, 9, 9, 9}},
+		{"FOUR_OF_A_KIND with 8 dice", []int{5, 5, 5, 5, 1, 2, 8, 9}, 9, FOUR_OF_A_KIND, []int{5, 5, 5, 5}},
+		{"THREE_PAIR with 8 dice", []int{1, 3, 5, 7, 9, 1, 3, 5}, 9, THREE_PAIR, []int{1, 1, 3, 3, 5, 5}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dice := generateDiceValues(tc.diceValues, tc.maxPips)
+			got := DetermineHandRank(dice)
+			if got != tc.expected {
+				t.Fatalf("%d dice test failed for %v: got %s, want %s",
+					len(tc.diceValues), tc.diceValues, got.String(), tc.expected.String())
+			}
+
+			foundDice := FindHandRankDice(got, dice, nil)
+			compareDiceSlicesUnordered(t, foundDice, tc.wantValues, tc.name, tc.diceValues, got)
+		})
+	}
+}
+
+// values past MAX_PIPS still rank, only the counts matter
+func TestCountSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []int
+		signature string
+		expected  HandRank
+	}{
+		{"full house", []int{3, 3, 3, 5, 5, 1}, "3+2+1", FULL_HOUSE},
+		{"three pair", []int{2, 2, 4, 4, 6, 6}, "2+2+2", THREE_PAIR},
+		{"big values", []int{12, 12, 12, 12, 15, 15, 15, 15}, "4+4", TWO_FOUR_OF_A_KIND},
+		{"eleven of a kind", []int{20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20}, "11", EIGHT_OF_A_KIND},
+		{"no matches", []int{10, 30, 50}, "1+1+1", HIGH_DIE},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			valueCount := map[int]int{}
+			for _, value := range tc.values {
+				valueCount[value]++
+			}
+			if got := countSignature(valueCount).String(); got != tc.signature {
+				t.Errorf("countSignature(%v) = %s; want %s", tc.values, got, tc.signature)
+			}
+			if got := rankValues(tc.values, DefaultStraightRules()); got != tc.expected {
+				t.Errorf("rankValues(%v) = %s; want %s", tc.values, got.String(), tc.expected.String())
+			}
+		})
+	}
+}
+
+// Test for every StraightRules option. findBestSingleConsecutive has to return the dice for the rule in play
+func TestStraightRules(t *testing.T) {
+	tests := []struct {
+		name               string
+		rules              StraightRules
+		diceValues         []int
+		maxPips            int
+		expected           HandRank
+		expectedDiceValues []int
+	}{
+		{"STRAIGHT_MAX (8 dice)", DefaultStraightRules(), []int{1, 2, 3, 4, 5, 6, 7, 8}, 8, STRAIGHT_MAX, []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{"STRAIGHT_MAX (9 dice)", DefaultStraightRules(), []int{9, 8, 7, 6, 5, 4, 3, 2, 1}, 9, STRAIGHT_MAX, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"longest run wins, not the last", DefaultStraightRules(), []int{1, 2, 3, 4, 5, 7, 8}, 8, STRAIGHT_LARGE, []int{1, 2, 3, 4, 5}},
+		{"default has no gaps", DefaultStraightRules(), []int{1, 2, 4, 5, 7}, 7, HIGH_DIE, nil},
+		{"default has no wrap", DefaultStraightRules(), []int{8, 9, 1, 2, 5}, 9, HIGH_DIE, nil},
+
+		{"gap makes small straight", StraightRules{MinLength: 4, Gaps: 1}, []int{1, 2, 4, 5}, 6, STRAIGHT_SMALL, []int{1, 2, 4, 5}},
+		{"gap makes large straight", StraightRules{MinLength: 4, Gaps: 1}, []int{2, 3, 4, 6, 7, 1}, 7, STRAIGHT_LARGER, []int{1, 2, 3, 4, 6, 7}},
+		{"only one gap", StraightRules{MinLength: 4, Gaps: 1}, []int{1, 3, 5, 7}, 7, HIGH_DIE, nil},
+		{"gap picks the highest", StraightRules{MinLength: 4, Gaps: 1}, []int{1, 2, 3, 5, 6}, 6, STRAIGHT_LARGE, []int{1, 2, 3, 5, 6}},
+
+		{"wrap 9 to 1", StraightRules{MinLength: 4, Wrap: true}, []int{8, 9, 1, 2, 5}, 9, STRAIGHT_SMALL, []int{8, 9, 1, 2}},
+		{"wrap large straight", StraightRules{MinLength: 4, Wrap: true}, []int{7, 8, 9, 1, 2}, 9, STRAIGHT_LARGE, []int{7, 8, 9, 1, 2}},
+		{"wrap never counts a value twice", StraightRules{MinLength: 4, Wrap: true}, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, 9, STRAIGHT_MAX, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"wrap with a gap", StraightRules{MinLength: 4, Gaps: 1, Wrap: true}, []int{7, 9, 1, 2, 3}, 9, STRAIGHT_LARGE, []int{7, 9, 1, 2, 3}},
+
+		{"short straight", StraightRules{MinLength: 3}, []int{3, 4, 5, 1}, 6, STRAIGHT_SMALL, []int{3, 4, 5}},
+		{"short straight beats a pair", StraightRules{MinLength: 3}, []int{3, 4, 5, 5}, 6, STRAIGHT_SMALL, []int{3, 4, 5}},
+		{"MinLength stops at 2", StraightRules{MinLength: 0}, []int{6, 2, 4}, 6, HIGH_DIE, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dice := generateDiceValues(tc.diceValues, tc.maxPips)
+			held := make([]*Die, len(dice))
+			for i := range dice {
+				held[i] = &dice[i]
+			}
+			table := NewHandTable()
+			table.Straights = tc.rules
+			got, _ := HandIndices(held, table)
+			if got != tc.expected {
+				t.Fatalf("HandIndices(%v) = %s; want %s", tc.diceValues, got.String(), tc.expected.String())
+			}
+
+			if tc.expectedDiceValues == nil {
+				return
+			}
+			compareStraightResult(t, findBestSingleConsecutive(dice, tc.rules), tc.expectedDiceValues, tc.name, tc.diceValues)
+
+			// the count check in compareDiceSlicesUnordered assumes the default lengths
+			_, gotValues := FindHandRankValues(got, dice, table)
+			sort.Ints(gotValues)
+			if !reflect.DeepEqual(gotValues, tc.expectedDiceValues) {
+				t.Errorf("FindHandRankValues(%s, %v) values = %v; want %v", got.String(), tc.diceValues, gotValues, tc.expectedDiceValues)
+			}
+		})
+	}
+}
+
+// a die wrapped the way the game wraps its dice, only reachable through ActiveFace
+type heldDie struct {
+	Die
+	name string
+}
+
+// HandIndices and FindHandRankIndices point back at the caller's dice, and agree with FindHandRankDice
+func TestHandIndices(t *testing.T) {
+	tests := []struct {
+		values      []int
+		wantHand    HandRank
+		wantIndices []int // sorted
+	}{
+		{[]int{4, 2, 4, 6}, ONE_PAIR, []int{0, 2}},
+		{[]int{3, 5, 3, 5, 3}, FULL_HOUSE, []int{0, 1, 2, 3, 4}},
+		{[]int{6, 1, 2, 3, 4}, STRAIGHT_SMALL, []int{1, 2, 3, 4}},
+		{[]int{2, 6, 1}, HIGH_DIE, []int{1}},
+		{[]int{}, NO_HAND, nil},
+	}
+
+	for _, tc := range tests {
+		values := generateDiceValues(tc.values, 6)
+		held := make([]*heldDie, len(values))
+		for i := range values {
+			held[i] = &heldDie{Die: values[i], name: fmt.Sprintf("die %d", i)}
+		}
+
+		hand, indices := HandIndices(held, nil)
+		sort.Ints(indices)
+		if hand != tc.wantHand || !reflect.DeepEqual(indices, tc.wantIndices) {
+			t.Errorf("HandIndices(%v) = %s %v; want %s %v", tc.values, hand.String(), indices, handRankStringMap[tc.wantHand], tc.wantIndices)
+		}
+
+		known := FindHandRankIndices(hand, held, nil)
+		sort.Ints(known)
+		if !reflect.DeepEqual(known, indices) {
+			t.Errorf("FindHandRankIndices(%s, %v) = %v; want %v", hand.String(), tc.values, known, indices)
+		}
+
+		var fromIndices []int
+		for _, i := range indices {
+			fromIndices = append(fromIndices, held[i].ActiveFace().Value())
+		}
+		copies := extractDiceValues(FindHandRankDice(hand, values, nil))
+		sort.Ints(fromIndices)
+		sort.Ints(copies)
+		if len(fromIndices) != len(copies) || len(copies) > 0 && !reflect.DeepEqual(fromIndices, copies) {
+			t.Errorf("%v: values from indices %v; FindHandRankDice gave %v", tc.values, fromIndices, copies)
+		}
+	}
+}
+
+// a split face counting as 5 is still the die that was passed in
+func TestHandIndicesTieBreaker(t *testing.T) {
+	held := []*Die{
+		{faces: []Face{MustSplitFace(2, 5)}},
+		{faces: []Face{MustFace(5)}},
+		{faces: []Face{MustFace(5)}},
+		{faces: []Face{MustFace(1)}},
+	}
+	hand, indices := HandIndices(held, nil)
+	if hand != THREE_OF_A_KIND {
+		t.Fatalf("hand = %s; want %s", hand.String(), handRankStringMap[THREE_OF_A_KIND])
+	}
+	sort.Ints(indices)
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(indices, want) {
+		t.Errorf("indices = %v; want %v", indices, want)
+	}
+}
+
+// Test for a straight and a group at once. the group needs its own dice but can share a value with the straight
+func TestCompoundHands(t *testing.T) {
+	tests := []struct {
+		name       string
+		diceValues []int
+		maxPips    int
+		expected   HandRank
+		wantValues []int // values of the dice from FindHandRankDice, both parts
+	}{
+		{"pair outside the straight", []int{1, 2, 3, 4, 6, 6}, 6, STRAIGHT_PAIR, []int{1, 2, 3, 4, 6, 6}},
+		{"pair value inside the straight", []int{1, 2, 3, 4, 3, 3}, 6, STRAIGHT_PAIR, []int{1, 2, 3, 3, 3, 4}},
+		{"one extra die is not a pair", []int{1, 2, 3, 4, 3}, 6, STRAIGHT_SMALL, []int{1, 2, 3, 4}},
+		{"the straight gives up a die for the pair", []int{1, 2, 3, 4, 5, 1}, 6, STRAIGHT_PAIR, []int{1, 1, 2, 3, 4, 5}},
+		{"the whole straight goes with the pair", []int{1, 2, 3, 4, 5, 3, 3}, 6, STRAIGHT_PAIR, []int{1, 2, 3, 3, 3, 4, 5}},
+		{"trips outside the straight", []int{2, 3, 4, 5, 1, 1, 1}, 6, STRAIGHT_TRIPS, []int{1, 1, 1, 2, 3, 4, 5}},
+		{"trips value inside the straight", []int{2, 3, 4, 5, 3, 3, 3}, 6, STRAIGHT_TRIPS, []int{2, 3, 3, 3, 3, 4, 5}},
+		{"trips can't take the straight's die", []int{2, 3, 4, 5, 3, 3}, 6, STRAIGHT_PAIR, []int{2, 3, 3, 3, 4, 5}},
+		{"STRAIGHT_LARGER beats STRAIGHT_PAIR", []int{1, 2, 3, 4, 5, 6, 3, 3}, 6, STRAIGHT_LARGER, []int{1, 2, 3, 4, 5, 6}},
+		{"STRAIGHT_TRIPS beats TWO_THREE_OF_A_KIND", []int{1, 2, 3, 4, 5, 5, 5, 2, 2}, 6, STRAIGHT_TRIPS, []int{1, 2, 3, 4, 5, 5, 5}},
+		{"STRAIGHT_TRIPS beats STRAIGHT_LARGER", []int{1, 2, 3, 4, 5, 6, 6, 6}, 6, STRAIGHT_TRIPS, []int{1, 2, 3, 4, 5, 6, 6, 6}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dice := generateDiceValues(tc.diceValues, tc.maxPips)
+			got := DetermineHandRank(dice)
+			if got != tc.expected {
+				t.Fatalf("DetermineHandRank(%v) = %s; want %s", tc.diceValues, got.String(), handRankStringMap[tc.expected])
+			}
+
+			foundDice := FindHandRankDice(got, dice, nil)
+			compareDiceSlicesUnordered(t, foundDice, tc.wantValues, tc.name, tc.diceValues, got)
+
+			held := make([]*Die, len(dice))
+			for i := range dice {
+				held[i] = &dice[i]
+			}
+			indices := FindHandRankIndices(got, held, nil)
+			seen := map[int]bool{}
+			for _, i := range indices {
+				if seen[i] {
+					t.Errorf("FindHandRankIndices(%v) = %v; die %d is used twice", tc.diceValues, indices, i)
+				}
+				seen[i] = true
+			}
+		})
+	}
+}
+
+// a compound hand outranks the straight and the group it is made of, so it can't pay less than any hand below it
+func TestCompoundHandMults(t *testing.T) {
+	for _, compound := range CompoundHands {
+		mult := handRankMult[compound.Rank]
+		for hand := HIGH_DIE; hand < UNKNOWN_HAND; hand++ {
+			switch {
+			case hand < compound.Rank && handRankMult[hand] > mult:
+				t.Errorf("%s pays x%v, less than the lower %s x%v", handRankStringMap[compound.Rank], mult, handRankStringMap[hand], handRankMult[hand])
+			case hand > compound.Rank && handRankMult[hand] < mult:
+				t.Errorf("%s pays x%v, more than the higher %s x%v", handRankStringMap[compound.Rank], mult, handRankStringMap[hand], handRankMult[hand])
+			}
+		}
+	}
+}
+
+// a higher HandRank is checked first, so paying less than a lower hand would make the dice worth less than they can be
+//
+// the listed hands have always paid less than the one before them, don't add to them
+func TestHandRankMultsNeverDecrease(t *testing.T) {
+	exceptions := map[HandRank]bool{
+		THREE_OF_A_KIND:     true, // 2 < TWO_PAIR 3
+		FULL_HOUSE:          true, // 5 < STRAIGHT_LARGE 5.5
+		TWO_THREE_OF_A_KIND: true, // 10 < STRAIGHT_LARGER 12.5
+		STRAIGHT_MAX:        true, // 25 < SEVEN_SEVENS 77
+	}
+
+	for hand := HIGH_DIE + 1; hand <= STRAIGHT_MAX; hand++ {
+		lower := hand - 1
+		if handRankMult[hand] < handRankMult[lower] && !exceptions[hand] {
+			t.Errorf("%s x%v pays less than the lower %s x%v", handRankStringMap[hand], handRankMult[hand], handRankStringMap[lower], handRankMult[lower])
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package dice
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// HandEntry is everything a run knows about one HandRank
+type HandEntry struct {
+	Name  string
+	Mult  float32    // base multiplier of the hand at level 1
+	Curve LevelCurve // how much each level adds to Mult
+	Level int        // starts at 1
+	Plays int        // how many times the hand was scored this run
+}
+
+// the multiplier of the hand at its current Level
+func (e HandEntry) Multiplier() float32 {
+	return e.Mult + e.Curve.Bonus(e.Level)
+}
+
+// LevelCurve is how a hand's mult grows as it levels up
+type LevelCurve struct {
+	Step   float32 // mult added going from level 1 to 2
+	Growth float32 // added to Step for every level after that
+}
+
+// the mult added on top of the base at level
+func (c LevelCurve) Bonus(level int) float32 {
+	var bonus float32
+	step := c.Step
+	for range level - 1 {
+		bonus += step
+		step += c.Growth
+	}
+	return bonus
+}
+
+// the curve every HandTable starts from. NO_HAND and UNKNOWN_HAND never level
+var handLevelCurves = map[HandRank]LevelCurve{
+	HIGH_DIE:            {Step: 0.5},
+	ONE_PAIR:            {Step: 0.5},
+	SNAKE_EYES:          {Step: 0.5, Growth: 0.25},
+	TWO_PAIR:            {Step: 0.75},
+	THREE_OF_A_KIND:     {Step: 0.5, Growth: 0.25},
+	STRAIGHT_SMALL:      {Step: 1.25},
+	STRAIGHT_LARGE:      {Step: 1.5},
+	FULL_HOUSE:          {Step: 1.25},
+	FOUR_OF_A_KIND:      {Step: 1.25, Growth: 0.25},
+	FIVE_OF_A_KIND:      {Step: 2},
+	STRAIGHT_PAIR:       {Step: 2},
+	THREE_PAIR:          {Step: 2},
+	CROWDED_HOUSE:       {Step: 2.5},
+	SIX_OF_A_KIND:       {Step: 2.5},
+	STRAIGHT_LARGER:     {Step: 3},
+	TWO_THREE_OF_A_KIND: {Step: 2.5},
+	STRAIGHT_TRIPS:      {Step: 2.5, Growth: 0.25},
+	OVERPOPULATED_HOUSE: {Step: 2.5},
+	STRAIGHT_LARGEST:    {Step: 3.5},
+	FULLEST_HOUSE:       {Step: 3.5},
+	SEVEN_OF_A_KIND:     {Step: 4},
+	FOUR_PAIR:           {Step: 4},
+	TWO_FOUR_OF_A_KIND:  {Step: 5},
+	EIGHT_OF_A_KIND:     {Step: 5.5},
+	SEVEN_SEVENS:        {Step: 7.7},
+	STRAIGHT_MAX:        {Step: 6},
+}
+
+// every PLAYS_PER_LEVEL times a hand is scored it goes up a level. 0 turns it off
+var PLAYS_PER_LEVEL = 5
+
+// HandTable is the HandRank registry for a single run.
+//
+// every run owns its own table, so upgrading a hand in one run never touches another.
+// the default mults/names in handRankMult and handRankStringMap are only used to fill a NewHandTable
+//
+//	table := NewHandTable()
+//	table.SetMult(FULL_HOUSE, 5)
+//	FULL_HOUSE.Multiplier(table) // 5
+type HandTable struct {
+	entries [UNKNOWN_HAND + 1]HandEntry
+
+	Straights StraightRules // what counts as a straight this run. not saved, the gems and jewelry that change it set it again
+}
+
+// returns a table with every HandRank at level 1, the default mult and curve, and 0 plays
+func NewHandTable() *HandTable {
+	table := &HandTable{Straights: DefaultStraightRules()}
+	for hand := range table.entries {
+		table.entries[hand] = HandEntry{
+			Name:  handRankStringMap[HandRank(hand)],
+			Mult:  handRankMult[HandRank(hand)],
+			Curve: handLevelCurves[HandRank(hand)],
+			Level: 1,
+		}
+	}
+	return table
+}
+
+// returns a copy of the table that can be changed without touching t
+func (t *HandTable) Clone() *HandTable {
+	clone := *t
+	return &clone
+}
+
+// the table's StraightRules, DefaultStraightRules for a nil table
+func (t *HandTable) straights() StraightRules {
+	if t == nil {
+		return DefaultStraightRules()
+	}
+	return t.Straights
+}
+
+func validHand(hand HandRank) bool {
+	return hand <= UNKNOWN_HAND
+}
+
+// returns a copy of the entry for hand. the zero HandEntry for an unknown HandRank
+func (t *HandTable) Entry(hand HandRank) HandEntry {
+	if !validHand(hand) {
+		return HandEntry{}
+	}
+	return t.entries[hand]
+}
+
+// the current multiplier of hand for this run, base mult plus its levels
+func (t *HandTable) Multiplier(hand HandRank) float32 {
+	return t.Entry(hand).Multiplier()
+}
+
+func (t *HandTable) Level(hand HandRank) int {
+	return t.Entry(hand).Level
+}
+
+// "Full House Lv.3"
+func (t *HandTable) LevelName(hand HandRank) string {
+	entry := t.Entry(hand)
+	return fmt.Sprintf("%s Lv.%d", entry.Name, entry.Level)
+}
+
+// "Full House Lv.3 7.5x", what the HUD shows for hand
+func (t *HandTable) Label(hand HandRank) string {
+	return t.LevelName(hand) + " " + MultString(t.Multiplier(hand))
+}
+
+// "7.5x", without trailing zeros
+func MultString(mult float32) string {
+	return strconv.FormatFloat(float64(mult), 'f', -1, 32) + "x"
+}
+
+func (t *HandTable) Name(hand HandRank) string {
+	return t.Entry(hand).Name
+}
+
+// overrides the base mult of hand for this run
+func (t *HandTable) SetMult(hand HandRank, mult float32) {
+	if !validHand(hand) {
+		return
+	}
+	t.entries[hand].Mult = mult
+}
+
+// raises hand by levels, from a shop item or the like. returns the hand's new level
+func (t *HandTable) LevelUp(hand HandRank, levels int) int {
+	if !validHand(hand) || levels <= 0 {
+		return t.Level(hand)
+	}
+	t.entries[hand].Level += levels
+	return t.entries[hand].Level
+}
+
+// counts a scored hand towards its play count, every PLAYS_PER_LEVEL plays it levels up.
+// returns true when this play leveled the hand
+func (t *HandTable) Played(hand HandRank) bool {
+	if !validHand(hand) {
+		return false
+	}
+	entry := &t.entries[hand]
+	entry.Plays++
+	if PLAYS_PER_LEVEL > 0 && entry.Plays%PLAYS_PER_LEVEL == 0 {
+		entry.Level++
+		return true
+	}
+	return false
+}
+
+// what a run save keeps of one hand. hands are saved by their default name
+// so reordering HandRank doesn't break saves, curves always come from the code
+type handEntryJSON struct {
+	Hand  string  `json:"hand"`
+	Mult  float32 `json:"mult"`
+	Level int     `json:"level"`
+	Plays int     `json:"plays"`
+}
+
+// only hands that differ from a NewHandTable are saved
+func (t *HandTable) MarshalJSON() ([]byte, error) {
+	defaults := NewHandTable()
+	hands := []handEntryJSON{}
+	for hand, entry := range t.entries {
+		def := defaults.entries[hand]
+		if entry.Mult == def.Mult && entry.Level == def.Level && entry.Plays == def.Plays {
+			continue
+		}
+		hands = append(hands, handEntryJSON{
+			Hand:  handRankStringMap[HandRank(hand)],
+			Mult:  entry.Mult,
+			Level: entry.Level,
+			Plays: entry.Plays,
+		})
+	}
+	return json.Marshal(hands)
+}
+
+// starts from a NewHandTable and applies every saved hand
+func (t *HandTable) UnmarshalJSON(data []byte) error {
+	var hands []handEntryJSON
+	if err := json.Unmarshal(data, &hands); err != nil {
+		return err
+	}
+
+	table := NewHandTable()
+	for _, saved := range hands {
+		hand, ok := handRankByName(saved.Hand)
+		if !ok {
+			return fmt.Errorf("unknown hand %q in save", saved.Hand)
+		}
+		if saved.Level < 1 || saved.Plays < 0 {
+			return fmt.Errorf("%s has level %d with %d plays in save", saved.Hand, saved.Level, saved.Plays)
+		}
+		entry := &table.entries[hand]
+		entry.Mult, entry.Level, entry.Plays = saved.Mult, saved.Level, saved.Plays
+	}
+	*t = *table
+	return nil
+}
+
+func handRankByName(name string) (HandRank, bool) {
+	for hand, handName := range handRankStringMap {
+		if handName == name {
+			return hand, true
+		}
+	}
+	return UNKNOWN_HAND, false
+}
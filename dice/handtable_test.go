@@ -0,0 +1,144 @@
+package dice
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHandTableDefaults(t *testing.T) {
+	table := NewHandTable()
+	for hand := NO_HAND; hand <= UNKNOWN_HAND; hand++ {
+		entry := table.Entry(hand)
+		if entry.Mult != handRankMult[hand] {
+			t.Errorf("%s mult = %.2f; want %.2f", hand.String(), entry.Mult, handRankMult[hand])
+		}
+		if entry.Name != hand.String() {
+			t.Errorf("%s name = %q; want %q", hand.String(), entry.Name, hand.String())
+		}
+		if entry.Level != 1 || entry.Plays != 0 {
+			t.Errorf("%s starts at level %d with %d plays; want level 1 with 0 plays", hand.String(), entry.Level, entry.Plays)
+		}
+	}
+
+	hand := FULL_HOUSE
+	if got := hand.Multiplier(nil); got != handRankMult[FULL_HOUSE] {
+		t.Errorf("Multiplier(nil) = %.2f; want the default %.2f", got, handRankMult[FULL_HOUSE])
+	}
+}
+
+// two runs can't change each other's mults
+func TestHandTableRunsAreSeparate(t *testing.T) {
+	runA := NewHandTable()
+	runB := NewHandTable()
+
+	runA.SetMult(FULL_HOUSE, 10)
+	runA.Played(FULL_HOUSE)
+	runA.Played(FULL_HOUSE)
+
+	hand := FULL_HOUSE
+	if got := hand.Multiplier(runA); got != 10 {
+		t.Errorf("run A Multiplier() = %.2f; want 10", got)
+	}
+	if got := hand.Multiplier(runB); got != handRankMult[FULL_HOUSE] {
+		t.Errorf("run B Multiplier() = %.2f; want the default %.2f", got, handRankMult[FULL_HOUSE])
+	}
+	if got := runA.Entry(FULL_HOUSE).Plays; got != 2 {
+		t.Errorf("run A plays = %d; want 2", got)
+	}
+	if got := runB.Entry(FULL_HOUSE).Plays; got != 0 {
+		t.Errorf("run B plays = %d; want 0", got)
+	}
+
+	clone := runA.Clone()
+	clone.SetMult(FULL_HOUSE, 1)
+	if got := runA.Multiplier(FULL_HOUSE); got != 10 {
+		t.Errorf("changing a clone changed the original, Multiplier() = %.2f; want 10", got)
+	}
+
+	dice := generateDiceValues([]int{3, 3, 3, 5, 5}, 6)
+	if got, want := ScoreHand(dice, FULL_HOUSE, runA), int(float32(3+3+3+5+5)*10); got != want {
+		t.Errorf("ScoreHand() = %d; want %d", got, want)
+	}
+}
+
+func TestHandLevels(t *testing.T) {
+	table := NewHandTable()
+	if got := table.Label(FULL_HOUSE); got != "Full House Lv.1 5x" {
+		t.Errorf("Label() = %q; want %q", got, "Full House Lv.1 5x")
+	}
+
+	if level := table.LevelUp(FULL_HOUSE, 2); level != 3 {
+		t.Errorf("LevelUp() = %d; want 3", level)
+	}
+	if got := table.Multiplier(FULL_HOUSE); got != 7.5 {
+		t.Errorf("level 3 Multiplier() = %.2f; want 7.50", got)
+	}
+	if got := table.Label(FULL_HOUSE); got != "Full House Lv.3 7.5x" {
+		t.Errorf("Label() = %q; want %q", got, "Full House Lv.3 7.5x")
+	}
+	if level := table.LevelUp(FULL_HOUSE, -1); level != 3 {
+		t.Errorf("LevelUp(-1) = %d; want the level to stay 3", level)
+	}
+
+	// SetMult changes the base, levels still add on top
+	table.SetMult(FULL_HOUSE, 10)
+	if got := table.Multiplier(FULL_HOUSE); got != 12.5 {
+		t.Errorf("Multiplier() after SetMult(10) = %.2f; want 12.50", got)
+	}
+
+	curve := LevelCurve{Step: 1, Growth: 0.5}
+	for level, want := range []float32{0, 0, 1, 2.5, 4.5} {
+		if got := curve.Bonus(level); got != want {
+			t.Errorf("Bonus(%d) = %.2f; want %.2f", level, got, want)
+		}
+	}
+}
+
+func TestHandLevelsFromPlays(t *testing.T) {
+	table := NewHandTable()
+	for play := 1; play <= PLAYS_PER_LEVEL*2; play++ {
+		leveled := table.Played(TWO_PAIR)
+		if want := play%PLAYS_PER_LEVEL == 0; leveled != want {
+			t.Errorf("play %d leveled = %t; want %t", play, leveled, want)
+		}
+	}
+	if got := table.Level(TWO_PAIR); got != 3 {
+		t.Errorf("level after %d plays = %d; want 3", PLAYS_PER_LEVEL*2, got)
+	}
+	if got := table.Level(ONE_PAIR); got != 1 {
+		t.Errorf("other hands level = %d; want 1", got)
+	}
+}
+
+func TestHandTableJSON(t *testing.T) {
+	table := NewHandTable()
+	table.LevelUp(FULL_HOUSE, 2)
+	table.Played(FULL_HOUSE)
+	table.SetMult(SNAKE_EYES, 4)
+
+	data, err := json.Marshal(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &HandTable{}
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *table {
+		t.Errorf("round trip changed the table\n%s", data)
+	}
+	if got := loaded.Label(FULL_HOUSE); got != "Full House Lv.3 7.5x" {
+		t.Errorf("loaded Label() = %q; want %q", got, "Full House Lv.3 7.5x")
+	}
+
+	for _, bad := range []string{
+		`[{"hand":"Royal Flush","mult":1,"level":1}]`,
+		`[{"hand":"Full House","mult":5,"level":0}]`,
+		`{}`,
+	} {
+		if err := json.Unmarshal([]byte(bad), &HandTable{}); err == nil {
+			t.Errorf("Unmarshal(%s) = nil; want an error", bad)
+		}
+	}
+}
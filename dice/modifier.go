@@ -0,0 +1,151 @@
+package dice
+
+import "fmt"
+
+// A Modifier is the buff (or debuff) that gets applied to
+// all varieties of items throughout DWR.
+//
+// Usage: use the 'enums' available for all valid Modifiers
+//
+// Every pip on a Face carries exactly one Modifier. The default Modifier (0) is ModNONE
+type Modifier uint8
+
+const (
+	ModNONE Modifier = iota // ModNONE is the default modifier. It has no additonal effects by default
+
+	// flat bonuses, added to the face's Score
+	ModBONUS     // +1 score
+	ModBONUS_BIG // +3 score
+
+	// multipliers, applied to the face's Score after every flat bonus
+	ModMULT   // x1.5 score
+	ModDOUBLE // x2 score
+
+	ModGOLD // +1 gold when the face is scored
+	ModWILD // the face counts as any value when determining a HandRank
+
+	// debuffs
+	ModCRACKED // -1 score
+	ModHOLLOW  // the pip does not count towards the face's Value
+	ModDULL    // x0.5 score
+
+	// weights, change how likely the face is to come up. see Die.Weight
+	ModLOADED // +1 roll weight, one loaded pip makes a face twice as likely as a blank one
+
+	NUM_MODIFIERS // not a modifier. used for validation and iterating
+)
+
+// the effect of a single pip carrying the Modifier.
+//
+// 0 for any field means no change
+type modifierInfo struct {
+	name   string
+	flat   int     // added to the face's score
+	mult   float32 // multiplies the face's score
+	value  int     // added to the face's value
+	gold   int     // gold earned when the face is scored
+	weight float32 // added to the face's roll weight
+	wild   bool    // face counts as any value
+	debuff bool
+}
+
+var modifierTable = [NUM_MODIFIERS]modifierInfo{
+	ModNONE:      {name: "None"},
+	ModBONUS:     {name: "Bonus", flat: 1},
+	ModBONUS_BIG: {name: "Big Bonus", flat: 3},
+	ModMULT:      {name: "Mult", mult: 1.5},
+	ModDOUBLE:    {name: "Double", mult: 2},
+	ModGOLD:      {name: "Gold", gold: 1},
+	ModWILD:      {name: "Wild", wild: true},
+	ModCRACKED:   {name: "Cracked", flat: -1, debuff: true},
+	ModHOLLOW:    {name: "Hollow", value: -1, debuff: true},
+	ModDULL:      {name: "Dull", mult: 0.5, debuff: true},
+	ModLOADED:    {name: "Loaded", weight: 1},
+}
+
+// false for any Modifier outside of the known catalogue
+func (m Modifier) Valid() bool {
+	return m < NUM_MODIFIERS
+}
+
+func (m Modifier) IsDebuff() bool {
+	return m.Valid() && modifierTable[m].debuff
+}
+
+func (m Modifier) String() string {
+	if !m.Valid() {
+		return fmt.Sprintf("Modifier(%d)", uint8(m))
+	}
+	return modifierTable[m].name
+}
+
+// NewModFace makes a face with one pip per given Modifier
+//
+//	NewModFace(ModNONE, ModBONUS, ModNONE) // 3 pips, the middle one is +1 score
+func NewModFace(mods ...Modifier) Face {
+	face := MustFace(len(mods))
+	for i, mod := range mods {
+		if !mod.Valid() {
+			panic(fmt.Errorf("could not make a dieface with modifier %d: %w", mod, ErrUnknownModifier))
+		}
+		face.pips[i] = mod
+	}
+	return face
+}
+
+// Returns a copy of the Modifier on each pip of the face
+func (f *Face) Modifiers() []Modifier {
+	mods := make([]Modifier, len(f.pips))
+	copy(mods, f.pips)
+	return mods
+}
+
+// true when any pip on the face is ModWILD
+func (f *Face) IsWild() bool {
+	for _, mod := range f.pips {
+		if modifierTable[mod].wild {
+			return true
+		}
+	}
+	return false
+}
+
+// Gold is the amount of gold earned when this face is scored
+func (f *Face) Gold() int {
+	var gold int
+	for _, mod := range f.pips {
+		gold += modifierTable[mod].gold
+	}
+	return gold
+}
+
+// ModifierScore is what a single pip's Modifier did to a face's Score
+type ModifierScore struct {
+	Pip      int // index of the pip on the face
+	Modifier Modifier
+	Added    float32 // how much the score changed, negative for debuffs. score before this pip * mult - score for mults
+}
+
+// returns every pip that changed the face's score, in the order they were applied, and the final Score.
+// see Face.Score for the resolution order
+func (f *Face) scoreModifiers() ([]ModifierScore, int) {
+	var mods []ModifierScore
+	score := float32(f.Value())
+
+	for i, mod := range f.pips {
+		if flat := modifierTable[mod].flat; flat != 0 {
+			score += float32(flat)
+			mods = append(mods, ModifierScore{Pip: i, Modifier: mod, Added: float32(flat)})
+		}
+	}
+
+	for i, mod := range f.pips {
+		if mult := modifierTable[mod].mult; mult != 0 {
+			before := score
+			score *= mult
+			mods = append(mods, ModifierScore{Pip: i, Modifier: mod, Added: score - before})
+		}
+	}
+
+	return mods, max(int(score), 0)
+}
@@ -0,0 +1,46 @@
+package dice
+
+import "testing"
+
+// TestFaceModifiers checks the resolution order of Value(), Score() and Gold()
+func TestFaceModifiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		mods      []Modifier
+		wantValue int
+		wantScore int
+		wantGold  int
+		wantWild  bool
+	}{
+		{"blank 3", []Modifier{ModNONE, ModNONE, ModNONE}, 3, 3, 0, false},
+		{"bonus", []Modifier{ModNONE, ModBONUS}, 2, 3, 0, false},
+		{"big bonus + bonus", []Modifier{ModBONUS_BIG, ModBONUS, ModNONE}, 3, 7, 0, false},
+		{"flat before mult", []Modifier{ModBONUS, ModDOUBLE}, 2, 6, 0, false},              // (2+1)*2
+		{"mults stack", []Modifier{ModDOUBLE, ModMULT, ModNONE, ModNONE}, 4, 12, 0, false}, // 4*2*1.5
+		{"gold", []Modifier{ModGOLD, ModGOLD, ModNONE}, 3, 3, 2, false},
+		{"wild keeps its pips", []Modifier{ModWILD, ModNONE}, 2, 2, 0, true},
+		{"cracked", []Modifier{ModCRACKED, ModNONE, ModNONE}, 3, 2, 0, false},
+		{"hollow lowers value and score", []Modifier{ModHOLLOW, ModNONE, ModNONE}, 2, 2, 0, false},
+		{"dull", []Modifier{ModDULL, ModNONE, ModNONE, ModNONE}, 4, 2, 0, false},
+		{"never below 0", []Modifier{ModHOLLOW, ModCRACKED}, 1, 0, 0, false},
+		{"value never below 0", []Modifier{ModHOLLOW}, 0, 0, 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			face := NewModFace(tc.mods...)
+			if got := face.Value(); got != tc.wantValue {
+				t.Errorf("Value() = %d; want %d", got, tc.wantValue)
+			}
+			if got := face.Score(); got != tc.wantScore {
+				t.Errorf("Score() = %d; want %d", got, tc.wantScore)
+			}
+			if got := face.Gold(); got != tc.wantGold {
+				t.Errorf("Gold() = %d; want %d", got, tc.wantGold)
+			}
+			if got := face.IsWild(); got != tc.wantWild {
+				t.Errorf("IsWild() = %t; want %t", got, tc.wantWild)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package dice
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// HandOdds is the chance of finishing with each HandRank, indexed by HandRank. adds up to 1
+//
+//	odds := RerollOdds(held, rolling, level.RollsLeft)
+//	odds[FULL_HOUSE] // 0.0694...
+type HandOdds [UNKNOWN_HAND + 1]float64
+
+// the chance of finishing with hand or anything better
+func (o HandOdds) AtLeast(hand HandRank) float64 {
+	var chance float64
+	for h := hand; h <= UNKNOWN_HAND; h++ {
+		chance += o[h]
+	}
+	return chance
+}
+
+// the most likely HandRank to finish with
+func (o HandOdds) Likeliest() HandRank {
+	var best HandRank
+	for hand := range o {
+		if o[hand] > o[best] {
+			best = HandRank(hand)
+		}
+	}
+	return best
+}
+
+func (o HandOdds) String() string {
+	var sb strings.Builder
+	for hand, chance := range o {
+		if chance == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s %.2f%%\n", handRankStringMap[HandRank(hand)], chance*100)
+	}
+	return sb.String()
+}
+
+// RerollOdds is the exact chance of finishing with each HandRank when rolling the unheld dice rollsLeft more times.
+//
+// every face of every rolling die is used, so custom faces (mods, wilds, splits) and loaded dice are accounted for.
+// held dice never change. after each roll the dice that make up the best hand are kept and the rest roll again,
+// the final hand is the best hand of every die.
+//
+// every outcome of every roll is ranked, milliseconds for 7 d6 and more for bigger dice.
+// nothing is kept between calls, so don't ask every frame
+//
+//	RerollOdds(held, rolling, 0) // the hand the held dice already show
+func RerollOdds(held []Die, rolling []Die, rollsLeft int) HandOdds {
+	if rollsLeft <= 0 || len(rolling) == 0 {
+		var odds HandOdds
+		odds[DetermineHandRank(held)] = 1
+		return odds
+	}
+
+	solver := newOddsSolver(held, rolling, newOddsMemo(nil))
+	return solver.solve(nil, rollsLeft).odds
+}
+
+// a face that can come up on a rolling die, and the chance it does
+type faceChance struct {
+	id     uint8
+	chance float64
+}
+
+// one possible result of rolling every die of a kind
+type kindOutcome struct {
+	ids    []uint8 // sorted
+	chance float64
+}
+
+// a die that came from a roll and was kept for the next one
+type keptDie struct {
+	kind uint8
+	id   uint8
+}
+
+// kept dice are always in the same order so they make the same state key
+func sortKept(kept []keptDie) {
+	slices.SortFunc(kept, func(a, b keptDie) int {
+		if a.kind != b.kind {
+			return int(a.kind) - int(b.kind)
+		}
+		return int(a.id) - int(b.id)
+	})
+}
+
+// the best hand of a set of faces and which of them make it up
+type rankedFaces struct {
+	hand  HandRank
+	hold  []bool  // indexed like the sorted ids that were ranked
+	score float64 // ScoreBreakdown Total of the faces
+}
+
+// what finishing from a state looks like
+type oddsResult struct {
+	odds  HandOdds
+	score float64 // expected ScoreBreakdown Total
+}
+
+// what stays true between solves with the same HandTable, the id of every face and the best hand of every set of them
+type oddsMemo struct {
+	faces   []Face // a face for each id
+	faceIds map[string]uint8
+	hands   *HandTable             // scores use this table's mults and StraightRules
+	ranks   map[string]rankedFaces // sorted ids -> the best hand
+}
+
+func newOddsMemo(hands *HandTable) *oddsMemo {
+	return &oddsMemo{
+		faceIds: map[string]uint8{},
+		hands:   hands,
+		ranks:   map[string]rankedFaces{},
+	}
+}
+
+// faces that rank the same share an id. dice with the same chances for the same ids are the same kind,
+// so rolling them can be counted as multisets instead of every ordering
+type oddsSolver struct {
+	*oddsMemo
+	held      []uint8        // ids of the held dice
+	kinds     [][]faceChance // the ids each kind of rolling die can show
+	kindCount []int          // how many rolling dice of each kind
+	dieKinds  []int          // the kind of each rolling die
+
+	outcomes map[[2]int][]kindOutcome // [kind, number of dice] -> every outcome
+	states   map[string]oddsResult    // rolls left + kept dice -> odds
+}
+
+func newOddsSolver(held []Die, rolling []Die, memo *oddsMemo) *oddsSolver {
+	s := &oddsSolver{
+		oddsMemo: memo,
+		outcomes: map[[2]int][]kindOutcome{},
+		states:   map[string]oddsResult{},
+	}
+
+	for i := range held {
+		s.held = append(s.held, s.faceID(held[i].ActiveFace()))
+	}
+
+	for i := range rolling {
+		chances := s.faceChances(&rolling[i])
+
+		kind := slices.IndexFunc(s.kinds, func(k []faceChance) bool { return slices.Equal(k, chances) })
+		if kind == -1 {
+			kind = len(s.kinds)
+			s.kinds = append(s.kinds, chances)
+			s.kindCount = append(s.kindCount, 0)
+		}
+		s.kindCount[kind]++
+		s.dieKinds = append(s.dieKinds, kind)
+	}
+
+	return s
+}
+
+// faces with the same pips, alternate values and base value rank and score the same
+func (m *oddsMemo) faceID(face *Face) uint8 {
+	// values are in MIN_FACE_VALUE - MAX_FACE_VALUE and offsets only a few pips past them, a byte each
+	key := make([]byte, 0, 2+len(face.pips)+len(face.alts))
+	key = append(key, byte(int8(face.offset)), byte(len(face.pips)))
+	for _, mod := range face.pips {
+		key = append(key, byte(mod))
+	}
+	for _, alt := range face.alts {
+		key = append(key, byte(int8(alt)))
+	}
+
+	id, ok := m.faceIds[string(key)]
+	if !ok {
+		if len(m.faces) > 255 {
+			panic(fmt.Errorf("too many different faces to calculate odds for (%d)", len(m.faces)))
+		}
+		id = uint8(len(m.faces))
+		m.faceIds[string(key)] = id
+		m.faces = append(m.faces, *face)
+	}
+	return id
+}
+
+// the chance of every face from Die.FaceChances, faces that rank the same are added together
+func (s *oddsSolver) faceChances(die *Die) []faceChance {
+	var chances []faceChance
+	for i, chance := range die.FaceChances() {
+		if chance == 0 {
+			continue
+		}
+		id := s.faceID(&die.faces[i])
+
+		j := slices.IndexFunc(chances, func(c faceChance) bool { return c.id == id })
+		if j == -1 {
+			chances = append(chances, faceChance{id: id, chance: chance})
+		} else {
+			chances[j].chance += chance
+		}
+	}
+	slices.SortFunc(chances, func(a, b faceChance) int { return int(a.id) - int(b.id) })
+	return chances
+}
+
+// every multiset of faces n dice of kind can show, with its chance
+func (s *oddsSolver) kindOutcomes(kind, n int) []kindOutcome {
+	if outcomes, ok := s.outcomes[[2]int{kind, n}]; ok {
+		return outcomes
+	}
+
+	var (
+		chances  = s.kinds[kind]
+		outcomes []kindOutcome
+		ids      = make([]uint8, 0, n)
+	)
+
+	// non-decreasing picks, weighted by the multinomial coefficient
+	var pick func(from int, chance float64, ways float64, run int)
+	pick = func(from int, chance float64, ways float64, run int) {
+		if len(ids) == n {
+			outcomes = append(outcomes, kindOutcome{ids: slices.Clone(ids), chance: chance * ways})
+			return
+		}
+		for c := from; c < len(chances); c++ {
+			nextRun := 1
+			if c == from && len(ids) > 0 && ids[len(ids)-1] == chances[c].id {
+				nextRun = run + 1
+			}
+			ids = append(ids, chances[c].id)
+			// ways * (dice placed) / (copies of this face so far)
+			pick(c, chance*chances[c].chance, ways*float64(len(ids))/float64(nextRun), nextRun)
+			ids = ids[:len(ids)-1]
+		}
+	}
+	pick(0, 1, 1, 0)
+
+	s.outcomes[[2]int{kind, n}] = outcomes
+	return outcomes
+}
+
+// the best hand of ids (sorted) and which of them make it up
+func (m *oddsMemo) rank(ids []uint8) rankedFaces {
+	key := string(ids)
+	if ranked, ok := m.ranks[key]; ok {
+		return ranked
+	}
+
+	dice := make([]Die, len(ids))
+	for i, id := range ids {
+		dice[i] = Die{faces: []Face{m.faces[id]}}
+	}
+	faces := activeFaces(dieRefs(dice))
+	rules := m.hands.straights()
+	hand, values := resolveValues(faces, rules)
+
+	indices := findHandIndices(hand, values, faceValues(faces), rules)
+	slices.Sort(indices)
+
+	ranked := rankedFaces{
+		hand:  hand,
+		hold:  make([]bool, len(ids)),
+		score: float64(scoreIndices(dice, values, indices, hand, m.hands).Total),
+	}
+	for _, i := range indices {
+		ranked.hold[i] = true
+	}
+
+	m.ranks[key] = ranked
+	return ranked
+}
+
+// a die on the table while solving. kind is -1 for held dice
+type oddsDie struct {
+	kind int
+	id   uint8
+}
+
+// the odds of finishing from kept dice with rollsLeft rolls of every other rolling die
+func (s *oddsSolver) solve(kept []keptDie, rollsLeft int) oddsResult {
+	stateKey := make([]byte, 0, 1+len(kept)*2)
+	stateKey = append(stateKey, byte(rollsLeft))
+	for _, die := range kept {
+		stateKey = append(stateKey, die.kind, die.id)
+	}
+	if result, ok := s.states[string(stateKey)]; ok {
+		return result
+	}
+
+	rolling := slices.Clone(s.kindCount)
+	for _, die := range kept {
+		rolling[die.kind]--
+	}
+
+	var (
+		result oddsResult
+		table  = make([]oddsDie, 0, len(s.held)+len(kept)+len(s.kindCount))
+		ids    []uint8
+	)
+	for _, id := range s.held {
+		table = append(table, oddsDie{kind: -1, id: id})
+	}
+	for _, die := range kept {
+		table = append(table, oddsDie{kind: int(die.kind), id: die.id})
+	}
+
+	var rollKind func(kind int, chance float64)
+	rollKind = func(kind int, chance float64) {
+		if kind == len(rolling) {
+			landed := slices.Clone(table)
+			slices.SortStableFunc(landed, func(a, b oddsDie) int {
+				if a.id != b.id {
+					return int(a.id) - int(b.id)
+				}
+				return a.kind - b.kind // held dice first, they are kept anyways
+			})
+
+			ids = ids[:0]
+			for _, die := range landed {
+				ids = append(ids, die.id)
+			}
+			ranked := s.rank(ids)
+
+			if rollsLeft == 1 {
+				result.odds[ranked.hand] += chance
+				result.score += chance * ranked.score
+				return
+			}
+
+			var nextKept []keptDie
+			for i, die := range landed {
+				if ranked.hold[i] && die.kind != -1 {
+					nextKept = append(nextKept, keptDie{kind: uint8(die.kind), id: die.id})
+				}
+			}
+			sortKept(nextKept)
+
+			next := s.solve(nextKept, rollsLeft-1)
+			for hand := range next.odds {
+				result.odds[hand] += chance * next.odds[hand]
+			}
+			result.score += chance * next.score
+			return
+		}
+
+		if rolling[kind] == 0 {
+			rollKind(kind+1, chance)
+			return
+		}
+
+		for _, outcome := range s.kindOutcomes(kind, rolling[kind]) {
+			size := len(table)
+			for _, id := range outcome.ids {
+				table = append(table, oddsDie{kind: kind, id: id})
+			}
+			rollKind(kind+1, chance*outcome.chance)
+			table = table[:size]
+		}
+	}
+	rollKind(0, 1)
+
+	s.states[string(stateKey)] = result
+	return result
+}
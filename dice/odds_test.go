@@ -0,0 +1,113 @@
+package dice
+
+import (
+	"math"
+	"testing"
+)
+
+// every ordering of rolling, one roll, ranked the slow way
+func bruteForceOdds(held []Die, rolling []Die) HandOdds {
+	var odds HandOdds
+	hand := append([]Die{}, held...)
+	hand = append(hand, rolling...)
+
+	var roll func(i int, chance float64)
+	roll = func(i int, chance float64) {
+		if i == len(rolling) {
+			odds[DetermineHandRank(hand)] += chance
+			return
+		}
+		die := &hand[len(held)+i]
+		for face := range die.faces {
+			die.activeFace = face
+			roll(i+1, chance/float64(len(die.faces)))
+		}
+	}
+	roll(0, 1)
+	return odds
+}
+
+func compareOdds(t *testing.T, got, want HandOdds) {
+	t.Helper()
+	for hand := range got {
+		if math.Abs(got[hand]-want[hand]) > 1e-9 {
+			t.Errorf("%s odds = %.6f; want %.6f", handRankStringMap[HandRank(hand)], got[hand], want[hand])
+		}
+	}
+}
+
+func TestRerollOddsTwoDice(t *testing.T) {
+	got := RerollOdds(nil, BlankDice(2), 1)
+
+	var want HandOdds
+	want[SNAKE_EYES] = 1.0 / 36
+	want[ONE_PAIR] = 5.0 / 36
+	want[HIGH_DIE] = 30.0 / 36
+	compareOdds(t, got, want)
+}
+
+func TestRerollOddsMatchesBruteForce(t *testing.T) {
+	custom := Die{faces: []Face{NewModFace(ModWILD), MustFace(2), MustSplitFace(3, 6), MustFace(9), NewModFace(ModHOLLOW, ModNONE), MustFace(2)}}
+
+	tests := []struct {
+		name    string
+		held    []Die
+		rolling []Die
+	}{
+		{"5 d6", nil, BlankDice(5)},
+		{"held pair + 4 d6", generateDiceValues([]int{4, 4}, 6), BlankDice(4)},
+		{"held straight start + d9s", generateDiceValues([]int{1, 2, 3}, 6), BlankDiceRange(3, 9)},
+		{"custom faces", generateDiceValues([]int{2}, 6), []Die{custom, custom, {faces: []Face{MustFace(1), MustFace(1), MustFace(5)}}, New6SidedDie([6]int{1, 1, 2, 2, 3, 3})}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			compareOdds(t, RerollOdds(tc.held, tc.rolling, 1), bruteForceOdds(tc.held, tc.rolling))
+		})
+	}
+}
+
+func TestRerollOddsMoreRolls(t *testing.T) {
+	held := generateDiceValues([]int{6, 6}, 6)
+	rolling := BlankDice(5)
+
+	none := RerollOdds(held, rolling, 0)
+	if none[ONE_PAIR] != 1 {
+		t.Errorf("no rolls left should finish with the held hand, got\n%s", none.String())
+	}
+
+	previous := none
+	for rolls := 1; rolls <= 3; rolls++ {
+		odds := RerollOdds(held, rolling, rolls)
+
+		var total float64
+		for _, chance := range odds {
+			total += chance
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("%d rolls: odds add up to %.9f; want 1", rolls, total)
+		}
+
+		// keeping the best hand means another roll can never make it worse
+		for hand := ONE_PAIR; hand <= UNKNOWN_HAND; hand++ {
+			if odds.AtLeast(hand)+1e-9 < previous.AtLeast(hand) {
+				t.Errorf("%d rolls: AtLeast(%s) = %.6f; was %.6f with one less roll", rolls, handRankStringMap[hand], odds.AtLeast(hand), previous.AtLeast(hand))
+			}
+		}
+		previous = odds
+	}
+}
+
+func BenchmarkRerollOddsSevenDice(b *testing.B) {
+	rolling := BlankDice(7)
+	for b.Loop() {
+		RerollOdds(nil, rolling, 2)
+	}
+}
+
+func BenchmarkRerollOddsPolyhedral(b *testing.B) {
+	rolling := []Die{NewDie(12), NewDie(20)}
+	for b.Loop() {
+		RerollOdds(nil, rolling, 2)
+	}
+}
@@ -0,0 +1,135 @@
+package dice
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DieScore is one die's part of a ScoreResult
+type DieScore struct {
+	Index     int             // index of the die in the dice that were scored
+	Value     int             // the value the die counted as for the HandRank. wild/split faces report what they resolved to
+	Base      int             // the face's .Value(), before any pip Modifier
+	Modifiers []ModifierScore // every pip that changed the score, in the order they were applied
+	Score     int             // what the die adds to the hand. Face.Score() then every OnDieScored effect
+	Gold      int             // gold earned from ModGOLD pips and effects
+}
+
+// ScoreResult is the full breakdown of a scored hand.
+//
+// anything that shows a score to the player (animation, HUD, run log) reads it from here
+// so what is shown is always what was applied
+//
+//	Total = int(Base * Mult)
+type ScoreResult struct {
+	Hand       HandRank
+	Dice       []DieScore // the dice that make up Hand, in the order they were given
+	Base       int        // sum of every DieScore.Score
+	Mult       float32
+	MultSource string // where Mult came from, the hand's name and level in the run's HandTable
+	Total      int
+	Gold       int           // sum of every DieScore.Gold and hand effect's Gold
+	Effects    []EffectScore // every ScoreEffect that triggered, in the order they resolved. see ApplyEffects
+}
+
+// scores dice showing the best HandRank, only the dice that make up the hand count.
+//
+// mults and StraightRules come from table, nil uses the defaults
+func ScoreBreakdown(dice []Die, table *HandTable) ScoreResult {
+	faces := activeFaces(dieRefs(dice))
+	rules := table.straights()
+	hand, values := resolveValues(faces, rules)
+	indices := findHandIndices(hand, values, faceValues(faces), rules)
+	slices.Sort(indices)
+	return scoreIndices(dice, values, indices, hand, table)
+}
+
+// scores every die given as hand, like ScoreHand.
+//
+// mults and StraightRules come from table, nil uses the defaults
+func ScoreHandBreakdown(dice []Die, hand HandRank, table *HandTable) ScoreResult {
+	_, values := resolveValues(activeFaces(dieRefs(dice)), table.straights())
+	indices := make([]int, len(dice))
+	for i := range dice {
+		indices[i] = i
+	}
+	return scoreIndices(dice, values, indices, hand, table)
+}
+
+func scoreIndices(dice []Die, values []int, indices []int, hand HandRank, table *HandTable) ScoreResult {
+	result := ScoreResult{
+		Hand: hand,
+		Dice: make([]DieScore, 0, len(indices)),
+		Mult: hand.Multiplier(table),
+	}
+	if table != nil {
+		result.MultSource = table.LevelName(hand)
+	} else {
+		result.MultSource = hand.String()
+	}
+
+	for _, i := range indices {
+		face := dice[i].ActiveFace()
+		mods, score := face.scoreModifiers()
+		dieScore := DieScore{
+			Index:     i,
+			Value:     values[i],
+			Base:      face.Value(),
+			Modifiers: mods,
+			Score:     score,
+			Gold:      face.Gold(),
+		}
+
+		result.Dice = append(result.Dice, dieScore)
+		result.Base += dieScore.Score
+		result.Gold += dieScore.Gold
+	}
+
+	result.Total = int(float32(result.Base) * result.Mult)
+	return result
+}
+
+// one line per die, one per hand effect, then the hand, for the run log
+//
+//	die 0: 3 +1.00 Bonus +4.00 Double +2.00 Ruby Ring = 10
+//	Gold Necklace x1.50
+//	Full House 10 x 4.50 = 45
+func (r ScoreResult) String() string {
+	var sb strings.Builder
+	for _, die := range r.Dice {
+		fmt.Fprintf(&sb, "die %d: %d", die.Index, die.Base)
+		for _, mod := range die.Modifiers {
+			fmt.Fprintf(&sb, " %+.2f %s", mod.Added, mod.Modifier.String())
+		}
+		for _, effect := range r.Effects {
+			if effect.Die == die.Index {
+				fmt.Fprintf(&sb, " %s", effect)
+			}
+		}
+		fmt.Fprintf(&sb, " = %d\n", die.Score)
+	}
+	for _, effect := range r.Effects {
+		if effect.Die == -1 {
+			fmt.Fprintf(&sb, "%s\n", effect)
+		}
+	}
+	fmt.Fprintf(&sb, "%s %d x %.2f = %d", r.MultSource, r.Base, r.Mult, r.Total)
+	return sb.String()
+}
+
+// "+2.00 x1.50 Ruby Ring", only what the effect changed
+func (e EffectScore) String() string {
+	var sb strings.Builder
+	if e.Added != 0 {
+		fmt.Fprintf(&sb, "%+.2f ", e.Added)
+	}
+	if e.Mult != 0 {
+		fmt.Fprintf(&sb, "x%.2f ", e.Mult)
+	}
+	if e.Gold != 0 {
+		fmt.Fprintf(&sb, "%+d gold ", e.Gold)
+	}
+	sb.WriteString(e.Source)
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package dice
+
+import "testing"
+
+func TestScoreBreakdown(t *testing.T) {
+	dice := []Die{
+		dieShowing(NewModFace(ModNONE, ModBONUS, ModNONE)),  // 3 +1
+		dieShowing(NewModFace(ModDOUBLE, ModNONE, ModGOLD)), // 3 x2, 1 gold
+		dieShowing(MustFace(3)),
+		dieShowing(MustFace(5)),
+		dieShowing(MustFace(1)),
+	}
+
+	result := ScoreBreakdown(dice, nil)
+	if result.Hand != THREE_OF_A_KIND {
+		t.Fatalf("Hand = %s; want %s", result.Hand.String(), handRankStringMap[THREE_OF_A_KIND])
+	}
+	if len(result.Dice) != 3 {
+		t.Fatalf("%d dice scored; want only the 3 in the hand", len(result.Dice))
+	}
+
+	want := []struct {
+		index, base, score, gold, mods int
+	}{
+		{0, 3, 4, 0, 1},
+		{1, 3, 6, 1, 1},
+		{2, 3, 3, 0, 0},
+	}
+	for i, w := range want {
+		got := result.Dice[i]
+		if got.Index != w.index || got.Base != w.base || got.Score != w.score || got.Gold != w.gold || len(got.Modifiers) != w.mods {
+			t.Errorf("die %d = %+v; want index %d base %d score %d gold %d with %d modifiers", i, got, w.index, w.base, w.score, w.gold, w.mods)
+		}
+	}
+	if added := result.Dice[1].Modifiers[0].Added; added != 3 {
+		t.Errorf("ModDOUBLE added %.2f; want 3", added)
+	}
+
+	if result.Base != 13 || result.Gold != 1 {
+		t.Errorf("Base = %d, Gold = %d; want 13, 1", result.Base, result.Gold)
+	}
+	if result.Total != int(13*handRankMult[THREE_OF_A_KIND]) {
+		t.Errorf("Total = %d; want %d", result.Total, int(13*handRankMult[THREE_OF_A_KIND]))
+	}
+	if result.Base != Score(dice) {
+		t.Errorf("Base = %d; Score() = %d", result.Base, Score(dice))
+	}
+}
+
+// the breakdown has to add up to what ScoreHand gives
+func TestScoreHandBreakdownMatchesScoreHand(t *testing.T) {
+	table := NewHandTable()
+	table.SetMult(FULL_HOUSE, 4.5)
+
+	dice := []Die{
+		dieShowing(NewModFace(ModNONE, ModNONE, ModMULT)),
+		dieShowing(NewModFace(ModCRACKED, ModNONE)),
+		dieShowing(NewModFace(ModHOLLOW, ModNONE, ModNONE, ModNONE)), // counts as 3
+		dieShowing(MustFace(2)),
+		dieShowing(NewModFace(ModDULL, ModBONUS_BIG)),
+	}
+
+	result := ScoreHandBreakdown(dice, FULL_HOUSE, table)
+	if result.Total != ScoreHand(dice, FULL_HOUSE, table) {
+		t.Errorf("Total = %d; ScoreHand() = %d", result.Total, ScoreHand(dice, FULL_HOUSE, table))
+	}
+	if result.Mult != 4.5 || result.MultSource != table.LevelName(FULL_HOUSE) {
+		t.Errorf("Mult = %.2f from %q; want 4.50 from %q", result.Mult, result.MultSource, table.LevelName(FULL_HOUSE))
+	}
+
+	sum := 0
+	for i, die := range result.Dice {
+		if die.Score != dice[i].ActiveFace().Score() {
+			t.Errorf("die %d Score = %d; Face.Score() = %d", i, die.Score, dice[i].ActiveFace().Score())
+		}
+		sum += die.Score
+	}
+	if sum != result.Base {
+		t.Errorf("dice add up to %d; Base = %d", sum, result.Base)
+	}
+}
@@ -1,103 +1,167 @@
 package dice
 
-//	 FIXME: will need to figure out how this works with rendering
+import "slices"
+
 //	 Score takes a set of dice, does the calculations
 //
 //		// returns the total of the .Score()
 //		// of every .ActiveFace() from the
 //		// best HandRank of the input dice
+//
+// see ScoreBreakdown for where the number came from
 func Score(dice []Die) int {
-	var total int
-	rank := DetermineHandRank(dice)
-	scoringDice := FindHandRankDice(rank, dice)
+	return ScoreBreakdown(dice, nil).Base
+}
+
+// takes a set of dice, determines their value and returns the int depending on the hand mult in table
+//
+// see ScoreHandBreakdown for where the number came from
+func ScoreHand(dice []Die, hand HandRank, table *HandTable) int {
+	return ScoreHandBreakdown(dice, hand, table).Total
+}
 
-	// fmt.Println(DiceString(scoringDice))
+// Facer is anything showing an active face. *Die is one, and so is anything that embeds a Die,
+// like the game's dice
+type Facer interface {
+	ActiveFace() *Face
+}
 
-	for _, d := range scoringDice {
-		total += d.ActiveFace().Score()
+// the active face of every die, in the same order
+func activeFaces[T Facer](dice []T) []*Face {
+	faces := make([]*Face, len(dice))
+	for i := range dice {
+		faces[i] = dice[i].ActiveFace()
 	}
-
-	return total
+	return faces
 }
 
-// takes a set of dice, determines their value and returns the int depending on the hand mult
-func ScoreHand(dice []Die, hand HandRank) int {
-	var total int
-	for _, d := range dice {
-		total += d.ActiveFace().Score()
+// dice as []*Die so they are Facers, without copying the dice
+func dieRefs(dice []Die) []*Die {
+	refs := make([]*Die, len(dice))
+	for i := range dice {
+		refs[i] = &dice[i]
 	}
-	return int(float32(total) * hand.Multiplier())
+	return refs
+}
+
+// HandIndices finds the best HandRank of dice and the indexes into dice of the ones that make it up.
+// straights follow table's StraightRules, nil uses the defaults
+//
+// nothing is copied, so callers know exactly which of their dice scored
+//
+//	hand, indices := HandIndices(heldDice, level.Hands) // []*Die, or anything else that is a Facer
+//	for _, i := range indices {
+//		heldDice[i] // scored
+//	}
+func HandIndices[T Facer](dice []T, table *HandTable) (HandRank, []int) {
+	faces := activeFaces(dice)
+	rules := table.straights()
+	hand, values := resolveValues(faces, rules)
+	return hand, findHandIndices(hand, values, faceValues(faces), rules)
 }
 
-// HAS IMPLEMENTATION FOR TOP LEVEL DIE IN /die.go
+// FindHandRankIndices is HandIndices for a hand that is already known.
 //
+// hand is assumed to be the best hand of dice, returns the indexes into dice that make it up
+func FindHandRankIndices[T Facer](hand HandRank, dice []T, table *HandTable) []int {
+	indices, _ := findHandRankIndices(hand, activeFaces(dice), table.straights())
+	return indices
+}
+
 // Find the hand that is associated with the given handrank.
 //
 // # The given handrank assumes that it is the BEST hand possible for the input dice
 //
-// Returns an error if not?? FIXME: not sure if this is worth doing
+// # Returns copies of the die that make up input handrank, use FindHandRankIndices to know which dice they were.
+// straights follow table's StraightRules, nil uses the defaults
+func FindHandRankDice(hand HandRank, dice []Die, table *HandTable) []Die {
+	indices := FindHandRankIndices(hand, dieRefs(dice), table)
+	if indices == nil {
+		return nil
+	}
+
+	foundDice := make([]Die, 0, len(indices))
+	for _, i := range indices {
+		foundDice = append(foundDice, dice[i])
+	}
+	return foundDice
+}
+
+// FindHandRankValues is FindHandRankDice that also returns the value each found die counted as.
+//
+// wild and split ("2 or 5") faces report the value they were resolved to, everything else is .Value()
+//
+//	dice [2, 2, WILD, 6]
+//	return [2, 2, WILD], [2, 2, 2]
+func FindHandRankValues(hand HandRank, dice []Die, table *HandTable) ([]Die, []int) {
+	indices, values := findHandRankIndices(hand, activeFaces(dieRefs(dice)), table.straights())
+	if indices == nil {
+		return nil, nil
+	}
+
+	foundDice := make([]Die, 0, len(indices))
+	foundValues := make([]int, 0, len(indices))
+	for _, i := range indices {
+		foundDice = append(foundDice, dice[i])
+		foundValues = append(foundValues, values[i])
+	}
+	return foundDice, foundValues
+}
+
+// the indexes of faces that make up hand, and the value every face counted as
+func findHandRankIndices(hand HandRank, faces []*Face, rules StraightRules) ([]int, []int) {
+	_, values := resolveValues(faces, rules)
+	return findHandIndices(hand, values, faceValues(faces), rules), values
+}
+
+// returns the indexes of values that make up input handrank, assumes handrank is the best hand
 //
-// # Returns the die that make up input handrank, assumes handrank is the best hand
-func FindHandRankDice(hand HandRank, dice []Die) []Die {
-	var foundDice []Die
+// own is each face's own Value(), the tie breaker for dice that count as the same value
+func findHandIndices(hand HandRank, values []int, own []int, rules StraightRules) []int {
+	var found []int
 	switch hand {
-	case HIGH_DIE:
-		var dieIndex, bestVal int
-		for i, die := range dice {
-			thisVal := die.ActiveFace().Value()
-			if thisVal > bestVal {
-				bestVal = thisVal
+	case HIGH_DIE: // values can be 0 or negative, the first die is the best until one beats it
+		dieIndex := 0
+		for i, value := range values {
+			if value > values[dieIndex] || value == values[dieIndex] && own[i] > own[dieIndex] {
 				dieIndex = i
 			}
 		}
-		foundDice = append(foundDice, dice[dieIndex])
-	case ONE_PAIR, SNAKE_EYES, THREE_OF_A_KIND, FOUR_OF_A_KIND, FIVE_OF_A_KIND, SIX_OF_A_KIND, SEVEN_OF_A_KIND, SEVEN_SEVENS:
-		foundDice = findMatchingValues(dice)
-	case FULL_HOUSE, CROWDED_HOUSE, TWO_PAIR, TWO_THREE_OF_A_KIND, OVERPOPULATED_HOUSE, FULLEST_HOUSE: // broken up for readability
-		foundDice = findMatchingValues(dice)
-	case THREE_PAIR:
-		foundDice = findMatchingValues(dice)
-		foundDice = filterThreePair(foundDice)
-	case STRAIGHT_SMALL, STRAIGHT_LARGE, STRAIGHT_LARGER, STRAIGHT_LARGEST:
-		foundDice = findBestSingleConsecutive(dice)
-	case STRAIGHT_MAX:
-		// TODO: impl
-		// ? MustLen(len(foundDice), 7)
+		if len(values) > 0 {
+			found = append(found, dieIndex)
+		}
+	case STRAIGHT_SMALL, STRAIGHT_LARGE, STRAIGHT_LARGER, STRAIGHT_LARGEST, STRAIGHT_MAX:
+		found = FindStraightIndices(values, own, rules)
 	case UNKNOWN_HAND, NO_HAND:
 	default:
-		return nil
+		if compound, ok := compoundHand(hand); ok {
+			found = findCompoundIndices(compound, values, own, rules)
+			break
+		}
+		def, ok := handDefinition(hand)
+		if !ok {
+			return nil
+		}
+		found = findGroupIndices(def, values, own)
 	}
 
-	// length := len(foundDice)
-	// switch hand {
-	// case HIGH_DIE:
-	// 	MustLen(length, 1, fmt.Sprintf("%d %s, %d length found, expected 1", hand, hand.String(), length))
-	// case ONE_PAIR, SNAKE_EYES:
-	// 	MustLen(length, 2, fmt.Sprintf("%d %s, %d length found, expected 2", hand, hand.String(), length))
-	// case THREE_OF_A_KIND: //TODO: FIXME:
-
-	// 	// MustLen(length, 3, fmt.Sprintf("%d %s, %d length found, expected 3", hand, hand.String(), length))
-	// case TWO_PAIR, STRAIGHT_SMALL, FOUR_OF_A_KIND:
-	// 	MustLen(length, 4, fmt.Sprintf("%d %s, %d length found, expected 4", hand, hand.String(), length))
-	// case STRAIGHT_LARGE, FULL_HOUSE, FIVE_OF_A_KIND:
-	// 	MustLen(length, 5, fmt.Sprintf("%d %s, %d length found, expected 5", hand, hand.String(), length))
-	// case SIX_OF_A_KIND, STRAIGHT_LARGER, TWO_THREE_OF_A_KIND, CROWDED_HOUSE, THREE_PAIR:
-	// 	MustLen(length, 6, fmt.Sprintf("%d %s, %d length found, expected 6", hand, hand.String(), length))
-	// case OVERPOPULATED_HOUSE, FULLEST_HOUSE, SEVEN_OF_A_KIND, SEVEN_SEVENS:
-	// 	MustLen(length, 7, fmt.Sprintf("%d %s, %d length found, expected 7", hand, hand.String(), length))
-	// default:
-	// MustLen(length, -1, hand.String()+" found. ???") // insta crash
-	// }
-
-	return foundDice
+	return found
 }
 
 // Calculates given slice of dice's active face's values.
 //
-// returns a HandRank that corresponds to the input dice
+// wild and split ("2 or 5") faces count as whichever value gives the best HandRank
+//
+// returns a HandRank that corresponds to the input dice, straights follow DefaultStraightRules
 func DetermineHandRank(dice []Die) HandRank {
+	hand, _ := resolveValues(activeFaces(dieRefs(dice)), DefaultStraightRules())
+	return hand
+}
+
+// returns a HandRank that corresponds to the input values, one value per die
+func rankValues(dieValues []int, rules StraightRules) HandRank {
 	var (
-		numDice       = len(dice)
+		numDice       = len(dieValues)
 		valueCount    = map[int]int{}
 		values        = []int{} // tracks the # of values' occurences from valueCount for comparisons. A slice of the values of valueCount
 		foundStraight HandRank
@@ -105,8 +169,7 @@ func DetermineHandRank(dice []Die) HandRank {
 	)
 
 	// gather occurences of unique values
-	for i := range numDice {
-		value := dice[i].ActiveFace().Value()
+	for _, value := range dieValues {
 		// starts from 0 bc of nil int
 		valueCount[value] = valueCount[value] + 1
 	}
@@ -117,8 +180,8 @@ func DetermineHandRank(dice []Die) HandRank {
 	}
 
 	// if a straight exists, assign check variable
-	if numDice >= STRAIGHT_SMALL_LENGTH { // has to be at least 4. STRAIGHT_SMALL
-		foundStraight = checkStraight(values)
+	if numDice >= rules.minLength() { // has to be at least 4 by default. STRAIGHT_SMALL
+		foundStraight = checkStraight(values, rules)
 	}
 
 	// determine type of hand based off # of unique values
@@ -126,7 +189,126 @@ func DetermineHandRank(dice []Die) HandRank {
 
 	// found straight vs handfound. Edge case for full houses and straights in the same hand
 	if foundStraight > handFound {
-		return foundStraight
+		handFound = foundStraight
+	}
+
+	// a straight and a group at once, both parts have to fit in the same dice
+	if foundStraight != NO_HAND {
+		handFound = max(handFound, checkCompound(valueCount, rules))
 	}
 	return handFound
 }
+
+// resolves the value every die's active face counts as.
+//
+// wild faces try every value the other faces can be and the values a straight away from them,
+// split faces try each of their Candidates(). The assignment with the highest HandRank wins,
+// ties go to the highest total value
+//
+// returns the best HandRank under rules and the value of each face, in the same order as faces
+func resolveValues(faces []*Face, rules StraightRules) (HandRank, []int) {
+	var (
+		values   = make([]int, len(faces))
+		splitIdx []int   // dice with more than one candidate
+		choices  [][]int // candidates of each splitIdx
+		wildIdx  []int
+	)
+
+	for i, face := range faces {
+		switch {
+		case face.IsWild():
+			wildIdx = append(wildIdx, i)
+		case len(face.alts) > 0:
+			splitIdx = append(splitIdx, i)
+			choices = append(choices, face.Candidates())
+		default:
+			values[i] = face.Value()
+		}
+	}
+
+	if len(splitIdx) == 0 && len(wildIdx) == 0 {
+		return rankValues(values, rules), values
+	}
+
+	// wilds only need to try the values other faces can be, and the values around them
+	// close enough to join a straight with them. values past MAX_PIPS (a d20) reach just as far
+	reach := STRAIGHT_MAX_LENGTH - 1 + max(rules.Gaps, 0)
+	var wildValues []int
+	for _, face := range faces {
+		if face.IsWild() {
+			continue
+		}
+		for _, value := range face.Candidates() {
+			for near := value - reach; near <= value+reach; near++ {
+				if validValue(near) == nil && !slices.Contains(wildValues, near) {
+					wildValues = append(wildValues, near)
+				}
+			}
+		}
+	}
+	if len(wildValues) == 0 { // every face is wild
+		for value := 1; value <= MAX_PIPS; value++ {
+			wildValues = append(wildValues, value)
+		}
+	}
+	slices.Sort(wildValues)
+
+	var (
+		bestHand   HandRank
+		bestSum    int
+		bestValues = make([]int, len(faces))
+		found      bool
+	)
+
+	try := func() {
+		hand := rankValues(values, rules)
+		var sum int
+		for _, value := range values {
+			sum += value
+		}
+		if !found || hand > bestHand || hand == bestHand && sum > bestSum {
+			found = true
+			bestHand = hand
+			bestSum = sum
+			copy(bestValues, values)
+		}
+	}
+
+	// wilds are interchangeable, so only non-decreasing assignments are tried
+	var assignWild func(i, from int)
+	assignWild = func(i, from int) {
+		if i == len(wildIdx) {
+			try()
+			return
+		}
+		for c := from; c < len(wildValues); c++ {
+			values[wildIdx[i]] = wildValues[c]
+			assignWild(i+1, c)
+		}
+	}
+
+	var assignSplit func(i int)
+	assignSplit = func(i int) {
+		if i == len(splitIdx) {
+			assignWild(0, 0)
+			return
+		}
+		for _, value := range choices[i] {
+			values[splitIdx[i]] = value
+			assignSplit(i + 1)
+		}
+	}
+
+	assignSplit(0)
+
+	return bestHand, bestValues
+}
+
+// each face's own Value(), before wild and split faces are resolved
+func faceValues(faces []*Face) []int {
+	values := make([]int, len(faces))
+	for i, face := range faces {
+		values[i] = face.Value()
+	}
+	return values
+}
@@ -33,13 +33,13 @@ func TestScoreComprehensive(t *testing.T) {
 		{"SEVEN_SEVENS", []int{7, 7, 7, 7, 7, 7, 7}, 7, 49},    // 7*7 (Needs maxPips=7)
 
 		// --- Straights (Assuming findBestSingleConsecutive works) ---
-		{"STRAIGHT_SMALL", []int{1, 2, 3, 4, 6}, 6, 10},                    // 1+2+3+4
-		{"STRAIGHT_SMALL (Out of Order)", []int{4, 6, 1, 3, 2}, 6, 10},     // 1+2+3+4
-		{"STRAIGHT_LARGE", []int{2, 3, 4, 5, 6}, 6, 20},                    // 2+3+4+5+6
-		{"STRAIGHT_LARGE (With Extras)", []int{1, 2, 3, 4, 5, 1}, 6, 15},   // 1+2+3+4+5
-		{"STRAIGHT_LARGER", []int{1, 2, 3, 4, 5, 6}, 6, 21},                // 1+2+3+4+5+6
-		{"STRAIGHT_LARGER (With Pair)", []int{1, 2, 3, 4, 5, 6, 6}, 6, 21}, // 1+2+3+4+5+6
-		{"STRAIGHT_LARGEST", []int{1, 2, 3, 4, 5, 6, 7}, 7, 28},            // 1+2+3+4+5+6+7 (Needs maxPips=7)
+		{"STRAIGHT_SMALL", []int{1, 2, 3, 4, 6}, 6, 10},                       // 1+2+3+4
+		{"STRAIGHT_SMALL (Out of Order)", []int{4, 6, 1, 3, 2}, 6, 10},        // 1+2+3+4
+		{"STRAIGHT_LARGE", []int{2, 3, 4, 5, 6}, 6, 20},                       // 2+3+4+5+6
+		{"STRAIGHT_PAIR (Large With Extras)", []int{1, 2, 3, 4, 5, 1}, 6, 16}, // 1+1 + 2+3+4+5
+		{"STRAIGHT_LARGER", []int{1, 2, 3, 4, 5, 6}, 6, 21},                   // 1+2+3+4+5+6
+		{"STRAIGHT_LARGER (With Pair)", []int{1, 2, 3, 4, 5, 6, 6}, 6, 21},    // 1+2+3+4+5+6
+		{"STRAIGHT_LARGEST", []int{1, 2, 3, 4, 5, 6, 7}, 7, 28},               // 1+2+3+4+5+6+7 (Needs maxPips=7)
 		// {"STRAIGHT_MAX", []int{}, 7, 0}, // Cannot test without definition/modifiers
 
 		// --- House Variants ---
@@ -51,7 +51,7 @@ func TestScoreComprehensive(t *testing.T) {
 
 		// --- Interaction Cases (Score reflects the BEST hand determined) ---
 		{"Interaction: Straight wins over Pair", []int{1, 2, 3, 4, 4}, 6, 10},               // Scores STRAIGHT_SMALL (1+2+3+4), not the pair
-		{"Interaction: 4oak wins over Straight", []int{1, 2, 3, 4, 4, 4, 4}, 6, 16},         // Scores FOUR_OF_A_KIND (4+4+4+4), not straight
+		{"Interaction: Straight Trips wins over 4oak", []int{1, 2, 3, 4, 4, 4, 4}, 6, 22},   // Scores STRAIGHT_TRIPS (1+2+3+4 + 4+4+4)
 		{"Interaction: Full House wins over Straight", []int{1, 2, 3, 3, 3, 2, 2}, 6, 15},   // Scores FULL_HOUSE (3+3+3+2+2), not straight 1-2-3
 		{"Interaction: Full House wins over Two Pair", []int{5, 5, 5, 2, 2, 1}, 6, 19},      // Scores FULL_HOUSE (5+5+5+2+2), not two pair
 		{"Interaction: Two Pair vs Snake Eyes", []int{1, 1, 2, 2}, 6, 6},                    // Scores TWO_PAIR (1+1+2+2=6) is higher than SNAKE_EYES (1+1=2)
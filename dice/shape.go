@@ -0,0 +1,462 @@
+package dice
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// DieShape is the solid a Die is made of. every Die is treated the same no matter its shape,
+// the shape only knows how its faces sit next to each other and how pips are laid out on them.
+//
+// a Die's shape comes from how many faces it has, see ShapeFor
+//
+//	die := NewDie(20)
+//	die.Shape().Name() // "d20"
+type DieShape interface {
+	Name() string
+	Sides() int
+
+	// faces that share an edge with face. a tumbling die rolls from a face to one of these
+	Adjacent(face int) []int
+
+	// the face that is read after the die lands on down.
+	// most dice are read from the face opposite the one on the table, a d4 is read from the one it lands on
+	UpFace(down int) int
+
+	// where the pips go on face, the same 3x3 grid used by the die shader
+	PipLayout(face int, pips int) [9]float32
+}
+
+// the layout of pips a face's outline fits best
+type pipGrid uint8
+
+const (
+	SQUARE_GRID   pipGrid = iota // d6
+	TRIANGLE_GRID                // d4, d8, d20
+	KITE_GRID                    // d10, d12 (pentagons are close enough)
+)
+
+// a DieShape described by which face touches which
+type polyhedron struct {
+	name      string
+	adjacency [][]int
+	opposite  []int // -1 when a face has no opposite face
+	readDown  bool  // read the face that landed on the table
+	grid      pipGrid
+}
+
+func (p *polyhedron) Name() string { return p.name }
+func (p *polyhedron) Sides() int   { return len(p.adjacency) }
+
+func (p *polyhedron) Adjacent(face int) []int {
+	return slices.Clone(p.adjacency[face])
+}
+
+func (p *polyhedron) UpFace(down int) int {
+	if p.readDown || p.opposite[down] == -1 {
+		return down
+	}
+	return p.opposite[down]
+}
+
+func (p *polyhedron) PipLayout(face int, pips int) [9]float32 {
+	switch p.grid {
+	case TRIANGLE_GRID:
+		return trianglePipLayout(pips)
+	case KITE_GRID:
+		return kitePipLayout(pips)
+	default:
+		return squarePipLayout(pips)
+	}
+}
+
+// the standard dice
+var (
+	D4  DieShape = newTetrahedron()
+	D6  DieShape = newCube()
+	D8  DieShape = newOctahedron()
+	D10 DieShape = newTrapezohedron()
+	D12 DieShape = newDodecahedron()
+	D20 DieShape = newIcosahedron()
+
+	shapes = []DieShape{D4, D6, D8, D10, D12, D20}
+)
+
+// returns the standard shape with sides faces. anything else is a shape where every face touches every other
+func ShapeFor(sides int) DieShape {
+	for _, shape := range shapes {
+		if shape.Sides() == sides {
+			return shape
+		}
+	}
+	return newShapeless(sides)
+}
+
+// the DieShape of the die, see ShapeFor
+func (d *Die) Shape() DieShape {
+	return ShapeFor(len(d.faces))
+}
+
+// the shortest way to tumble from one face to another, both included
+//
+//	Tumble(D6, FrontFace, BehindFace) // [FrontFace, LeftFace, BehindFace]
+func Tumble(shape DieShape, from int, to int) []int {
+	previous := make([]int, shape.Sides())
+	for i := range previous {
+		previous[i] = -1
+	}
+	previous[from] = from
+
+	queue := []int{from}
+	for len(queue) > 0 && previous[to] == -1 {
+		face := queue[0]
+		queue = queue[1:]
+		for _, next := range shape.Adjacent(face) {
+			if previous[next] == -1 {
+				previous[next] = face
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	path := []int{to}
+	for face := to; face != from; face = previous[face] {
+		path = append(path, previous[face])
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// faces opposite each other are never adjacent, every other pair is
+func adjacencyFromOpposites(opposite []int) [][]int {
+	adjacency := make([][]int, len(opposite))
+	for face := range opposite {
+		for other := range opposite {
+			if other != face && other != opposite[face] {
+				adjacency[face] = append(adjacency[face], other)
+			}
+		}
+	}
+	return adjacency
+}
+
+// faces are the cube's, see FrontFace
+func newCube() *polyhedron {
+	opposite := []int{
+		FrontFace:  BehindFace,
+		LeftFace:   RightFace,
+		BottomFace: TopFace,
+		TopFace:    BottomFace,
+		RightFace:  LeftFace,
+		BehindFace: FrontFace,
+	}
+	return &polyhedron{
+		name:      "d6",
+		adjacency: adjacencyFromOpposites(opposite),
+		opposite:  opposite,
+		grid:      SQUARE_GRID,
+	}
+}
+
+// every face touches the other 3, a d4 lands on a face instead of showing one
+func newTetrahedron() *polyhedron {
+	shape := newShapeless(4)
+	shape.name = "d4"
+	shape.readDown = true
+	shape.grid = TRIANGLE_GRID
+	return shape
+}
+
+// a face for each octant, the bits of the index are the signs of x, y and z
+func newOctahedron() *polyhedron {
+	shape := &polyhedron{name: "d8", grid: TRIANGLE_GRID}
+	for face := range 8 {
+		shape.adjacency = append(shape.adjacency, []int{face ^ 1, face ^ 2, face ^ 4})
+		shape.opposite = append(shape.opposite, face^7)
+	}
+	return shape
+}
+
+// faces 0-4 go around the top point, 5-9 around the bottom point twisted half a face
+func newTrapezohedron() *polyhedron {
+	shape := &polyhedron{name: "d10", grid: KITE_GRID}
+	for face := range 10 {
+		i := face % 5
+		if face < 5 {
+			shape.adjacency = append(shape.adjacency, []int{(i + 4) % 5, (i + 1) % 5, 5 + i, 5 + (i+4)%5})
+			shape.opposite = append(shape.opposite, 5+(i+2)%5)
+		} else {
+			shape.adjacency = append(shape.adjacency, []int{5 + (i+4)%5, 5 + (i+1)%5, i, (i + 1) % 5})
+			shape.opposite = append(shape.opposite, (i+3)%5)
+		}
+	}
+	return shape
+}
+
+// the 12 corners of an icosahedron, (0, ±1, ±φ) and its rotations
+func icosahedronCorners() [][3]float64 {
+	phi := (1 + math.Sqrt(5)) / 2
+	var corners [][3]float64
+	for _, a := range []float64{-1, 1} {
+		for _, b := range []float64{-phi, phi} {
+			corners = append(corners, [3]float64{0, a, b}, [3]float64{a, b, 0}, [3]float64{b, 0, a})
+		}
+	}
+	return corners
+}
+
+// corners of an icosahedron are 2 apart when they share an edge
+func icosahedronEdge(a, b [3]float64) bool {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return math.Abs(dx*dx+dy*dy+dz*dz-4) < 1e-9
+}
+
+func negated(a, b [3]float64) bool {
+	return math.Abs(a[0]+b[0])+math.Abs(a[1]+b[1])+math.Abs(a[2]+b[2]) < 1e-9
+}
+
+// a d12's faces sit where an icosahedron's corners are, faces touch when the corners share an edge
+func newDodecahedron() *polyhedron {
+	corners := icosahedronCorners()
+	shape := &polyhedron{name: "d12", grid: KITE_GRID}
+	for _, corner := range corners {
+		var adjacent []int
+		opposite := -1
+		for j, other := range corners {
+			if icosahedronEdge(corner, other) {
+				adjacent = append(adjacent, j)
+			}
+			if negated(corner, other) {
+				opposite = j
+			}
+		}
+		shape.adjacency = append(shape.adjacency, adjacent)
+		shape.opposite = append(shape.opposite, opposite)
+	}
+	return shape
+}
+
+// a d20's faces are the triangles of 3 corners that all share edges
+func newIcosahedron() *polyhedron {
+	corners := icosahedronCorners()
+
+	var faces [][3]int
+	for a := range corners {
+		for b := a + 1; b < len(corners); b++ {
+			for c := b + 1; c < len(corners); c++ {
+				if icosahedronEdge(corners[a], corners[b]) && icosahedronEdge(corners[b], corners[c]) && icosahedronEdge(corners[a], corners[c]) {
+					faces = append(faces, [3]int{a, b, c})
+				}
+			}
+		}
+	}
+
+	center := func(face [3]int) [3]float64 {
+		var sum [3]float64
+		for _, corner := range face {
+			for axis := range sum {
+				sum[axis] += corners[corner][axis]
+			}
+		}
+		return sum
+	}
+
+	shape := &polyhedron{name: "d20", grid: TRIANGLE_GRID}
+	for _, face := range faces {
+		var adjacent []int
+		opposite := -1
+		for j, other := range faces {
+			shared := 0
+			for _, corner := range face {
+				if slices.Contains(other[:], corner) {
+					shared++
+				}
+			}
+			if shared == 2 {
+				adjacent = append(adjacent, j)
+			}
+			if negated(center(face), center(other)) {
+				opposite = j
+			}
+		}
+		shape.adjacency = append(shape.adjacency, adjacent)
+		shape.opposite = append(shape.opposite, opposite)
+	}
+	return shape
+}
+
+// not a real solid. every face touches every other and there is no opposite face
+func newShapeless(sides int) *polyhedron {
+	shape := &polyhedron{name: fmt.Sprintf("d%d", sides), grid: SQUARE_GRID}
+	for face := range sides {
+		var adjacent []int
+		for other := range sides {
+			if other != face {
+				adjacent = append(adjacent, other)
+			}
+		}
+		shape.adjacency = append(shape.adjacency, adjacent)
+		shape.opposite = append(shape.opposite, -1)
+	}
+	return shape
+}
+
+// used for shader uniforms, the layout of a d6. see DieShape.PipLayout
+//
+// TODO: bool might not work long term, we may need another check for gems/mods in pips
+//
+// returns a 9 length true/false array
+//
+// each index corresponds to where on the die face the pip is
+//
+//	[false, false, false,
+//	 false, true, false,
+//	 false, false, false] // die face with 1 pip
+func squarePipLayout(pips int) [9]float32 {
+	pipLoc := [9]float32{}
+
+	switch pips {
+	case 1:
+		pipLoc[middle] = 1.0
+	case 2:
+		pipLoc[topRight] = 1.0
+		pipLoc[bottomLeft] = 1.0
+	case 3:
+		pipLoc[topLeft] = 1.0
+		pipLoc[middle] = 1.0
+		pipLoc[bottomRight] = 1.0
+	case 4:
+		pipLoc[topLeft] = 1.0
+		pipLoc[topRight] = 1.0
+		pipLoc[bottomRight] = 1.0
+		pipLoc[bottomLeft] = 1.0
+	case 5:
+		pipLoc[topLeft] = 1.0
+		pipLoc[topRight] = 1.0
+		pipLoc[middle] = 1.0
+		pipLoc[bottomRight] = 1.0
+		pipLoc[bottomLeft] = 1.0
+	case 6:
+		pipLoc[topLeft] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[topRight] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomRight] = 1.0
+	case 7:
+		pipLoc[middle] = 1.0
+		pipLoc[topLeft] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[topRight] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomRight] = 1.0
+	case 8:
+		pipLoc[topLeft] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[topRight] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomRight] = 1.0
+		pipLoc[topMiddle] = 1.0
+		pipLoc[bottomMiddle] = 1.0
+	case 9:
+		pipLoc[topLeft] = 1.0
+		pipLoc[topMiddle] = 1.0
+		pipLoc[topRight] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[middle] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomRight] = 1.0
+		pipLoc[bottomMiddle] = 1.0
+		pipLoc[bottomLeft] = 1.0
+	}
+
+	return pipLoc
+}
+
+// pips on a triangle, point up. fills the 3x3 grid from the bottom row
+//
+//	[false, true, false,
+//	 false, false, false,
+//	 true, false, true] // triangle face with 3 pips
+func trianglePipLayout(pips int) [9]float32 {
+	pipLoc := [9]float32{}
+
+	switch pips {
+	case 1:
+		pipLoc[middle] = 1.0
+	case 2:
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[bottomRight] = 1.0
+	case 3:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[bottomRight] = 1.0
+	case 4:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[middle] = 1.0
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[bottomRight] = 1.0
+	case 5:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[bottomRight] = 1.0
+	case 6:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[bottomMiddle] = 1.0
+		pipLoc[bottomRight] = 1.0
+	case 7:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[middle] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomLeft] = 1.0
+		pipLoc[bottomMiddle] = 1.0
+		pipLoc[bottomRight] = 1.0
+	default: // too many to make a triangle
+		return squarePipLayout(pips)
+	}
+
+	return pipLoc
+}
+
+// pips on a kite/pentagon, point up. a diamond where it fits
+//
+//	[false, true, false,
+//	 true, false, true,
+//	 false, true, false] // kite face with 4 pips
+func kitePipLayout(pips int) [9]float32 {
+	pipLoc := [9]float32{}
+
+	switch pips {
+	case 2:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[bottomMiddle] = 1.0
+	case 3:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[middle] = 1.0
+		pipLoc[bottomMiddle] = 1.0
+	case 4:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomMiddle] = 1.0
+	case 5:
+		pipLoc[topMiddle] = 1.0
+		pipLoc[middleLeft] = 1.0
+		pipLoc[middle] = 1.0
+		pipLoc[middleRight] = 1.0
+		pipLoc[bottomMiddle] = 1.0
+	default:
+		return squarePipLayout(pips)
+	}
+
+	return pipLoc
+}
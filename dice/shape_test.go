@@ -0,0 +1,123 @@
+package dice
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStandardShapes(t *testing.T) {
+	tests := []struct {
+		shape    DieShape
+		name     string
+		sides    int
+		adjacent int // every face of these solids touches the same number of faces
+	}{
+		{D4, "d4", 4, 3},
+		{D6, "d6", 6, 4},
+		{D8, "d8", 8, 3},
+		{D10, "d10", 10, 4},
+		{D12, "d12", 12, 5},
+		{D20, "d20", 20, 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.shape.Name() != tc.name || tc.shape.Sides() != tc.sides {
+				t.Fatalf("got %s with %d sides; want %s with %d", tc.shape.Name(), tc.shape.Sides(), tc.name, tc.sides)
+			}
+			if ShapeFor(tc.sides) != tc.shape {
+				t.Errorf("ShapeFor(%d) = %s", tc.sides, ShapeFor(tc.sides).Name())
+			}
+
+			ups := map[int]bool{}
+			for face := range tc.sides {
+				adjacent := tc.shape.Adjacent(face)
+				if len(adjacent) != tc.adjacent {
+					t.Errorf("face %d touches %d faces %v; want %d", face, len(adjacent), adjacent, tc.adjacent)
+				}
+				for _, other := range adjacent {
+					if other == face || !slices.Contains(tc.shape.Adjacent(other), face) {
+						t.Errorf("face %d touches %d but not the other way around", face, other)
+					}
+				}
+
+				up := tc.shape.UpFace(face)
+				if up != face && slices.Contains(adjacent, up) {
+					t.Errorf("landing on %d shows %d, a face right next to it", face, up)
+				}
+				ups[up] = true
+
+				path := Tumble(tc.shape, 0, face)
+				if path[0] != 0 || path[len(path)-1] != face {
+					t.Errorf("Tumble(0, %d) = %v", face, path)
+				}
+				for i := 1; i < len(path); i++ {
+					if !slices.Contains(tc.shape.Adjacent(path[i-1]), path[i]) {
+						t.Errorf("Tumble(0, %d) = %v jumps from %d to %d", face, path, path[i-1], path[i])
+					}
+				}
+			}
+
+			// every face can come up, so rolls stay fair
+			if len(ups) != tc.sides {
+				t.Errorf("only %d of %d faces can come up", len(ups), tc.sides)
+			}
+		})
+	}
+}
+
+func TestNewDieShapes(t *testing.T) {
+	for _, sides := range []int{4, 6, 8, 10, 12, 20} {
+		die := NewDie(sides)
+		if die.Shape().Sides() != sides {
+			t.Errorf("NewDie(%d) is a %s", sides, die.Shape().Name())
+		}
+		for i := range die.faces {
+			if pips := die.faces[i].NumPips(); pips < 1 || pips > MAX_PIPS {
+				t.Errorf("NewDie(%d) face %d has %d pips", sides, i, pips)
+			}
+		}
+
+		for face := range die.faces {
+			die.activeFace = face
+			if len(die.LocationsPips()) != 6*9 {
+				t.Fatalf("NewDie(%d) LocationsPips() has %d floats; want %d", sides, len(die.LocationsPips()), 6*9)
+			}
+			slot := die.ShaderFace()
+			if slot < 0 || die.shaderFaces()[slot] != face {
+				t.Errorf("NewDie(%d) face %d is drawn in slot %d", sides, face, slot)
+			}
+		}
+	}
+
+	// a d6 is drawn exactly as its faces are
+	die := NewDie(6)
+	die.activeFace = TopFace
+	if die.ShaderFace() != TopFace {
+		t.Errorf("d6 ShaderFace() = %d; want %d", die.ShaderFace(), TopFace)
+	}
+	layouts := die.LocationsPips()
+	for face := range die.faces {
+		var pips float32
+		for _, pip := range layouts[face*9 : face*9+9] {
+			pips += pip
+		}
+		if int(pips) != die.faces[face].NumPips() {
+			t.Errorf("d6 slot %d shows %.0f pips; want %d", face, pips, die.faces[face].NumPips())
+		}
+	}
+}
+
+func TestPipLayouts(t *testing.T) {
+	for _, shape := range shapes {
+		for pips := 1; pips <= MAX_PIPS; pips++ {
+			var count float32
+			for _, pip := range shape.PipLayout(0, pips) {
+				count += pip
+			}
+			if int(count) != pips {
+				t.Errorf("%s layout for %d pips has %.0f pips", shape.Name(), pips, count)
+			}
+		}
+	}
+}
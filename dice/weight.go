@@ -0,0 +1,129 @@
+package dice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Loaded dice
+//
+// every face has a roll weight, the chance of a face coming up is its weight over the weight of every face.
+//
+//	weight = base weight (DEFAULT_WEIGHT or Die.SetWeight) + every pip's weight (ModLOADED)
+//
+// a die where every face weighs the same rolls like a fair die
+
+// the base weight of every face on a new Die
+const DEFAULT_WEIGHT float32 = 1
+
+var ErrInvalidWeight = errors.New("invalid weight")
+
+// sets the base weight of a face, curses and shop items use it to load a die.
+//
+//	die.SetWeight(5, 3) // face 5 comes up 3 times as often as a blank face
+func (d *Die) SetWeight(face int, weight float32) error {
+	if face < 0 || face >= len(d.faces) {
+		return fmt.Errorf("%w: face %d of a %d sided die", ErrInvalidWeight, face, len(d.faces))
+	}
+	if weight < 0 || math.IsNaN(float64(weight)) || math.IsInf(float64(weight), 0) {
+		return fmt.Errorf("%w: %v", ErrInvalidWeight, weight)
+	}
+
+	if d.weights == nil {
+		if weight == DEFAULT_WEIGHT {
+			return nil
+		}
+		d.weights = make([]float32, len(d.faces))
+		for i := range d.weights {
+			d.weights[i] = DEFAULT_WEIGHT
+		}
+	}
+	d.weights[face] = weight
+	return nil
+}
+
+// puts every face back to DEFAULT_WEIGHT. ModLOADED pips still count
+func (d *Die) ResetWeights() {
+	d.weights = nil
+}
+
+// the base weight of a face, without its pips
+func (d *Die) BaseWeight(face int) float32 {
+	if d.weights == nil {
+		return DEFAULT_WEIGHT
+	}
+	return d.weights[face]
+}
+
+// the roll weight of a face, never below 0
+func (d *Die) Weight(face int) float32 {
+	return max(d.BaseWeight(face)+d.faces[face].Weight(), 0)
+}
+
+// what the face's pips add to its roll weight
+func (f *Face) Weight() float32 {
+	var weight float32
+	for _, mod := range f.pips {
+		weight += modifierTable[mod].weight
+	}
+	return weight
+}
+
+// true when some faces are more likely to come up than others
+func (d *Die) IsLoaded() bool {
+	for face := range d.faces {
+		if d.Weight(face) != d.Weight(0) {
+			return true
+		}
+	}
+	return false
+}
+
+// the chance of each face coming up on a Roll, indexed like the die's faces. adds up to 1
+//
+//	NewDie(6).FaceChances() // [0.1666.. 0.1666.. ...]
+//
+// a die where every face weighs 0 rolls like a fair die
+func (d *Die) FaceChances() []float64 {
+	chances := make([]float64, len(d.faces))
+
+	var total float64
+	for face := range d.faces {
+		chances[face] = float64(d.Weight(face))
+		total += chances[face]
+	}
+
+	for face := range chances {
+		if total == 0 {
+			chances[face] = 1 / float64(len(chances))
+		} else {
+			chances[face] /= total
+		}
+	}
+	return chances
+}
+
+// the chance of face coming up on a Roll
+func (d *Die) FaceChance(face int) float64 {
+	return d.FaceChances()[face]
+}
+
+// the face that comes up for roll, a number from 0 up to 1
+func (d *Die) pickWeighted(roll float64) int {
+	chances := d.FaceChances()
+	for face, chance := range chances {
+		if roll < chance {
+			return face
+		}
+		roll -= chance
+	}
+
+	// rounding left a sliver at the end, it belongs to the last face that can come up
+	for face := len(chances) - 1; face > 0; face-- {
+		if chances[face] > 0 {
+			return face
+		}
+	}
+	return 0
+}
@@ -0,0 +1,183 @@
+package dice
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ninesl/dice-will-roll/rng"
+)
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestFaceChances(t *testing.T) {
+	fair := NewDie(6)
+	if fair.IsLoaded() {
+		t.Error("NewDie(6) should not be loaded")
+	}
+	for face, chance := range fair.FaceChances() {
+		if !closeTo(chance, 1.0/6) {
+			t.Errorf("fair face %d chance = %v; want 1/6", face, chance)
+		}
+	}
+
+	loaded := NewDie(6)
+	if err := loaded.SetWeight(5, 3); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.IsLoaded() {
+		t.Error("die with a face weighing 3 should be loaded")
+	}
+	if got := loaded.FaceChance(5); !closeTo(got, 3.0/8) {
+		t.Errorf("FaceChance(5) = %v; want 3/8", got)
+	}
+	if got := loaded.FaceChance(0); !closeTo(got, 1.0/8) {
+		t.Errorf("FaceChance(0) = %v; want 1/8", got)
+	}
+
+	// a loaded pip adds to the face's base weight
+	pips := Die{faces: []Face{MustFace(1), NewModFace(ModLOADED, ModNONE), NewModFace(ModLOADED, ModLOADED, ModNONE)}}
+	want := []float64{1.0 / 6, 2.0 / 6, 3.0 / 6}
+	for face, chance := range pips.FaceChances() {
+		if !closeTo(chance, want[face]) {
+			t.Errorf("loaded pips face %d chance = %v; want %v", face, chance, want[face])
+		}
+	}
+
+	// weighing 0 never comes up, unless nothing weighs anything
+	zero := NewDie(4)
+	zero.SetWeight(0, 0)
+	zero.SetWeight(1, 0)
+	if zero.FaceChance(0) != 0 || !closeTo(zero.FaceChance(2), 0.5) {
+		t.Errorf("zero weights FaceChances() = %v", zero.FaceChances())
+	}
+	zero.SetWeight(2, 0)
+	zero.SetWeight(3, 0)
+	if zero.IsLoaded() || !closeTo(zero.FaceChance(0), 0.25) {
+		t.Errorf("every face weighing 0 FaceChances() = %v; want a fair die", zero.FaceChances())
+	}
+
+	zero.ResetWeights()
+	if zero.weights != nil || zero.IsLoaded() {
+		t.Errorf("ResetWeights() left %v", zero.weights)
+	}
+}
+
+func TestSetWeightInvalid(t *testing.T) {
+	die := NewDie(6)
+	for _, tc := range []struct {
+		face   int
+		weight float32
+	}{
+		{-1, 1},
+		{6, 1},
+		{0, -0.5},
+		{0, float32(math.NaN())},
+		{0, float32(math.Inf(1))},
+	} {
+		if err := die.SetWeight(tc.face, tc.weight); !errors.Is(err, ErrInvalidWeight) {
+			t.Errorf("SetWeight(%d, %v) error = %v; want %v", tc.face, tc.weight, err, ErrInvalidWeight)
+		}
+	}
+	if die.weights != nil {
+		t.Errorf("invalid weights were kept %v", die.weights)
+	}
+}
+
+// chi-squared goodness of fit of rolled faces against FaceChances
+func rollChiSquared(die Die, seed uint64, rolls int) (float64, []int) {
+	streams := rng.New(seed)
+	counts := make([]int, len(die.faces))
+	for range rolls {
+		die.Roll(streams.Dice)
+		counts[die.ActiveFaceIndex()]++
+	}
+
+	var chi float64
+	for face, chance := range die.FaceChances() {
+		expected := chance * float64(rolls)
+		if expected == 0 {
+			if counts[face] > 0 {
+				return math.Inf(1), counts
+			}
+			continue
+		}
+		diff := float64(counts[face]) - expected
+		chi += diff * diff / expected
+	}
+	return chi, counts
+}
+
+func TestWeightedRollDistribution(t *testing.T) {
+	const ROLLS = 60000
+
+	sixes := NewDie(6)
+	sixes.SetWeight(5, 4)
+
+	d20 := NewDie(20)
+	d20.SetWeight(19, 5)
+	d20.SetWeight(0, 0)
+
+	pips := Die{faces: []Face{MustFace(1), NewModFace(ModLOADED, ModNONE), MustFace(3), MustFace(4)}}
+
+	tests := []struct {
+		name     string
+		die      Die
+		critical float64 // chi-squared for p = 0.001 with sides-1 degrees of freedom
+	}{
+		{"fair d6", NewDie(6), 20.52},
+		{"loaded toward 6", sixes, 20.52},
+		{"loaded d20", d20, 43.82},
+		{"loaded pip", pips, 16.27},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// the seeds are fixed so the test never flakes, but none of them should be rare
+			for _, seed := range []uint64{1, 2, 3} {
+				chi, counts := rollChiSquared(tc.die, seed, ROLLS)
+				if chi > tc.critical {
+					t.Errorf("seed %d: chi-squared %.2f > %.2f, rolled %v for %v", seed, chi, tc.critical, counts, tc.die.FaceChances())
+				}
+			}
+		})
+	}
+}
+
+// fair dice roll the same faces they did before weights existed, so old seeds replay the same
+func TestFairRollUnchanged(t *testing.T) {
+	streams, check := rng.New(99), rng.New(99)
+	die := NewDie(8)
+	for range 100 {
+		down := check.Dice.IntN(8)
+		die.Roll(streams.Dice)
+		if die.ActiveFaceIndex() != D8.UpFace(down) {
+			t.Fatalf("fair d8 rolled face %d; want %d", die.ActiveFaceIndex(), D8.UpFace(down))
+		}
+	}
+}
+
+func TestRerollOddsLoaded(t *testing.T) {
+	loaded := New6SidedDie([6]int{1, 2, 3, 4, 5, 6})
+	loaded.SetWeight(5, 5) // 6 comes up half the time
+
+	chances := loaded.FaceChances()
+	snakeEyes := chances[0] * chances[0]
+	var pair float64
+	for _, chance := range chances[1:] {
+		pair += chance * chance
+	}
+
+	odds := RerollOdds(nil, []Die{loaded, loaded}, 1)
+	if !closeTo(odds[ONE_PAIR], pair) {
+		t.Errorf("odds of a pair = %v; want %v", odds[ONE_PAIR], pair)
+	}
+	if !closeTo(odds[SNAKE_EYES], snakeEyes) {
+		t.Errorf("odds of snake eyes = %v; want %v", odds[SNAKE_EYES], snakeEyes)
+	}
+	if !closeTo(odds[HIGH_DIE], 1-pair-snakeEyes) {
+		t.Errorf("odds of a high die = %v; want %v", odds[HIGH_DIE], 1-pair-snakeEyes)
+	}
+}
@@ -106,7 +106,7 @@ func (g *Game) DrawDice(screen *ebiten.Image, opts *ebiten.DrawImageOptions) {
 		}
 
 		g.opts.shader.Uniforms["FaceLayouts"] = g.Dice[i].LocationsPips()
-		g.opts.shader.Uniforms["ActiveFace"] = g.Dice[i].ActiveFaceIndex()
+		g.opts.shader.Uniforms["ActiveFace"] = g.Dice[i].ShaderFace()
 		g.opts.shader.Uniforms["Height"] = g.Dice[i].Height
 		g.opts.shader.Uniforms["Direction"] = g.Dice[i].Direction.KageVec2()
 		g.opts.shader.Uniforms["Velocity"] = g.Dice[i].Velocity.KageVec2()
@@ -0,0 +1,240 @@
+// Package events is the ordered pipeline everything that reacts to play subscribes to.
+//
+// gems, jewelry and level rules all hook into the same events instead of
+// being wired into the level one by one. nothing here needs ebiten, so every rule can be unit tested.
+// pip modifiers aren't subscribers, they are already in a die's score (dice.ScoreHandBreakdown) when the events run
+//
+//	pipeline := events.NewPipeline()
+//	pipeline.Subscribe(events.AfterHandScored, events.RULE_ORDER, "Shale", func(ctx *events.Context) {
+//		if ctx.Hand == dice.FULL_HOUSE {
+//			ctx.MultScore(2)
+//		}
+//	})
+//	result, ctx := pipeline.ScoreHand(scoringDice, hand, table)
+package events
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ninesl/dice-will-roll/dice"
+)
+
+type Event uint8
+
+const (
+	OnRoll           Event = iota // dice were rolled. Dice are the dice that rolled
+	OnHold                        // a die was put in the hand
+	OnRelease                     // a die was taken back out of the hand
+	BeforeHandScored              // a hand is about to be scored, before any die
+	OnDieScored                   // one die of the hand was scored. Die is its DieScore
+	AfterHandScored               // every die was scored, before the Total is worked out
+	OnLevelCleared                // every rock of the level is gone
+	OnLevelStarted                // the run moved on to a level. Level is its index
+	OnGameOver                    // hands ran out with rocks left
+	OnRunCleared                  // the last level of the run was cleared
+
+	NUM_EVENTS
+)
+
+var eventStringMap = map[Event]string{
+	OnRoll:           "OnRoll",
+	OnHold:           "OnHold",
+	OnRelease:        "OnRelease",
+	BeforeHandScored: "BeforeHandScored",
+	OnDieScored:      "OnDieScored",
+	AfterHandScored:  "AfterHandScored",
+	OnLevelCleared:   "OnLevelCleared",
+	OnLevelStarted:   "OnLevelStarted",
+	OnGameOver:       "OnGameOver",
+	OnRunCleared:     "OnRunCleared",
+}
+
+func (e Event) String() string {
+	if name, ok := eventStringMap[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("Event(%d)", uint8(e))
+}
+
+// subscribers of an event run lowest order first, then in the order they subscribed
+const (
+	GEM_ORDER     = 100
+	JEWELRY_ORDER = 200
+	RULE_ORDER    = 300
+)
+
+// Context is what a subscriber sees of an event and how it changes things.
+//
+// score changes only count while a hand is scored, rolls and rocks are added up
+// for whoever fired the event to apply
+type Context struct {
+	Event  Event
+	Hand   dice.HandRank
+	Dice   []*dice.Die       // the dice the event is about. for scoring events, the scored dice in DieScore.Index order
+	Result *dice.ScoreResult // the hand being scored, nil outside of scoring
+	Die    *dice.DieScore    // the die being scored, OnDieScored only
+	Level  int               // index of the run's level, for OnLevelCleared and the run events
+
+	Rolls int // rolls added by subscribers
+	Rocks int // rocks added by subscribers, negative breaks rocks
+
+	source    string  // the subscriber being run
+	baseAdded float32 // score added to the hand before the dice were scored
+	goldAdded int     // gold added to the hand itself
+}
+
+// adds to the die being scored OnDieScored, otherwise to the hand's Base
+func (c *Context) AddScore(amount float32) {
+	c.Apply(dice.EffectScore{Added: amount})
+}
+
+// multiplies the die being scored OnDieScored, otherwise the hand's Mult
+func (c *Context) MultScore(mult float32) {
+	c.Apply(dice.EffectScore{Mult: mult})
+}
+
+func (c *Context) AddGold(gold int) {
+	c.Apply(dice.EffectScore{Gold: gold})
+}
+
+func (c *Context) AddRolls(rolls int) {
+	c.Rolls += rolls
+}
+
+func (c *Context) AddRocks(rocks int) {
+	c.Rocks += rocks
+}
+
+// applies a score change and adds it to Result.Effects. Source is the subscriber's name when empty.
+//
+// see dice.ScoreResult.Apply. does nothing outside of scoring
+func (c *Context) Apply(score dice.EffectScore) {
+	if c.Result == nil {
+		return
+	}
+	if score.Source == "" {
+		score.Source = c.source
+	}
+
+	if c.Event == OnDieScored && c.Die != nil {
+		c.Result.Apply(score, c.Die)
+		return
+	}
+
+	c.Result.Apply(score, nil)
+	if c.Event == BeforeHandScored {
+		c.baseAdded += score.Added
+	}
+	c.goldAdded += score.Gold
+}
+
+type Handler func(ctx *Context)
+
+// returned by Subscribe, used to Unsubscribe
+type Subscription int
+
+type subscriber struct {
+	id      Subscription
+	order   int
+	source  string
+	handler Handler
+}
+
+// Pipeline runs the subscribers of each event in order. the same subscribers and the same
+// events always give the same result
+type Pipeline struct {
+	subscribers [NUM_EVENTS][]subscriber
+	nextID      Subscription
+}
+
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// adds handler to event. source is the name shown in the score breakdown, order is one of the *_ORDER
+func (p *Pipeline) Subscribe(event Event, order int, source string, handler Handler) Subscription {
+	p.nextID++
+	subs := append(p.subscribers[event], subscriber{id: p.nextID, order: order, source: source, handler: handler})
+	slices.SortStableFunc(subs, func(a, b subscriber) int {
+		return a.order - b.order
+	})
+	p.subscribers[event] = subs
+	return p.nextID
+}
+
+// removes a subscriber from whichever event it was on
+func (p *Pipeline) Unsubscribe(sub Subscription) {
+	for event := range p.subscribers {
+		p.subscribers[event] = slices.DeleteFunc(p.subscribers[event], func(s subscriber) bool {
+			return s.id == sub
+		})
+	}
+}
+
+// runs every subscriber of ctx.Event, in order
+func (p *Pipeline) Fire(ctx *Context) {
+	if ctx.Event >= NUM_EVENTS {
+		return
+	}
+	for _, sub := range p.subscribers[ctx.Event] {
+		ctx.source = sub.source
+		sub.handler(ctx)
+	}
+	ctx.source = ""
+}
+
+// fires event for dice outside of scoring. the returned Context has the rolls and rocks to apply
+func (p *Pipeline) FireDice(event Event, hand dice.HandRank, dice ...*dice.Die) *Context {
+	ctx := &Context{Event: event, Hand: hand, Dice: dice}
+	p.Fire(ctx)
+	return ctx
+}
+
+// fires a run event (OnLevelStarted, OnLevelCleared, OnGameOver, OnRunCleared) for the run's level at index level.
+// the returned Context has the rolls and rocks to apply
+func (p *Pipeline) FireLevel(event Event, level int) *Context {
+	ctx := &Context{Event: event, Level: level}
+	p.Fire(ctx)
+	return ctx
+}
+
+// scores every die given as hand, like dice.ScoreHandBreakdown, with every scoring event on the way
+//
+//  1. BeforeHandScored
+//  2. OnDieScored for each die, in order
+//  3. Base is worked out again from the dice, plus what BeforeHandScored added
+//  4. AfterHandScored
+//  5. Total = Base * Mult
+//
+// the returned Context has the rolls and rocks to apply
+func (p *Pipeline) ScoreHand(scored []*dice.Die, hand dice.HandRank, table *dice.HandTable) (dice.ScoreResult, *Context) {
+	showing := make([]dice.Die, len(scored))
+	for i, die := range scored {
+		showing[i] = *die
+	}
+	result := dice.ScoreHandBreakdown(showing, hand, table)
+
+	ctx := &Context{Event: BeforeHandScored, Hand: hand, Dice: scored, Result: &result}
+	p.Fire(ctx)
+
+	ctx.Event = OnDieScored
+	for i := range result.Dice {
+		ctx.Die = &result.Dice[i]
+		p.Fire(ctx)
+	}
+	ctx.Die = nil
+
+	result.Base, result.Gold = 0, ctx.goldAdded
+	for _, die := range result.Dice {
+		result.Base += die.Score
+		result.Gold += die.Gold
+	}
+	result.Base = max(int(float32(result.Base)+ctx.baseAdded), 0)
+
+	ctx.Event = AfterHandScored
+	p.Fire(ctx)
+
+	result.Total = int(float32(result.Base) * result.Mult)
+	return result, ctx
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ninesl/dice-will-roll/dice"
+)
+
+func showing(values ...int) []*dice.Die {
+	var showing []*dice.Die
+	for _, value := range values {
+		die := dice.New6SidedDie([6]int{value, value, value, value, value, value})
+		showing = append(showing, &die)
+	}
+	return showing
+}
+
+func TestSubscriberOrder(t *testing.T) {
+	pipeline := NewPipeline()
+	var ran []string
+	record := func(ctx *Context) { ran = append(ran, ctx.source) }
+
+	pipeline.Subscribe(OnRoll, RULE_ORDER, "rule", record)
+	pipeline.Subscribe(OnRoll, 0, "first", record)
+	jewelry := pipeline.Subscribe(OnRoll, JEWELRY_ORDER, "jewelry 1", record)
+	pipeline.Subscribe(OnRoll, JEWELRY_ORDER, "jewelry 2", record)
+	pipeline.Subscribe(OnRoll, GEM_ORDER, "gem", record)
+	pipeline.Subscribe(OnHold, 0, "hold", record)
+
+	pipeline.FireDice(OnRoll, dice.NO_HAND)
+	if want := "first,gem,jewelry 1,jewelry 2,rule"; strings.Join(ran, ",") != want {
+		t.Errorf("ran %v; want %s", ran, want)
+	}
+
+	ran = nil
+	pipeline.Unsubscribe(jewelry)
+	pipeline.FireDice(OnRoll, dice.NO_HAND)
+	if want := "first,gem,jewelry 2,rule"; strings.Join(ran, ",") != want {
+		t.Errorf("after Unsubscribe ran %v; want %s", ran, want)
+	}
+}
+
+func TestRollsAndRocks(t *testing.T) {
+	pipeline := NewPipeline()
+	pipeline.Subscribe(OnRoll, RULE_ORDER, "extra roll on a 6", func(ctx *Context) {
+		for _, die := range ctx.Dice {
+			if die.ActiveFace().Value() == 6 {
+				ctx.AddRolls(1)
+			}
+		}
+	})
+	pipeline.Subscribe(OnLevelCleared, RULE_ORDER, "cave in", func(ctx *Context) {
+		ctx.AddRocks(-5)
+		ctx.AddScore(100) // nothing is being scored
+	})
+
+	if ctx := pipeline.FireDice(OnRoll, dice.NO_HAND, showing(6, 2, 6)...); ctx.Rolls != 2 {
+		t.Errorf("Rolls = %d; want 2", ctx.Rolls)
+	}
+	if ctx := pipeline.FireDice(OnLevelCleared, dice.NO_HAND); ctx.Rocks != -5 || ctx.Result != nil {
+		t.Errorf("Rocks = %d; want -5", ctx.Rocks)
+	}
+}
+
+func TestScoreHand(t *testing.T) {
+	pipeline := NewPipeline()
+	pipeline.Subscribe(BeforeHandScored, RULE_ORDER, "head start", func(ctx *Context) {
+		ctx.AddScore(10)
+	})
+	pipeline.Subscribe(OnDieScored, GEM_ORDER, "threes x2", func(ctx *Context) {
+		if ctx.Die.Value == 3 {
+			ctx.MultScore(2)
+			ctx.AddGold(1)
+		}
+	})
+	pipeline.Subscribe(AfterHandScored, JEWELRY_ORDER, "pairs x3", func(ctx *Context) {
+		if ctx.Hand == dice.TWO_PAIR {
+			ctx.MultScore(3)
+			ctx.AddRocks(-1)
+		}
+	})
+
+	scored := showing(3, 3, 5, 5)
+	plain := dice.ScoreHandBreakdown([]dice.Die{*scored[0], *scored[1], *scored[2], *scored[3]}, dice.TWO_PAIR, nil)
+
+	result, ctx := pipeline.ScoreHand(scored, dice.TWO_PAIR, nil)
+	// 10 + 6 + 6 + 5 + 5
+	if result.Base != 32 || result.Mult != plain.Mult*3 || result.Total != int(32*plain.Mult*3) {
+		t.Errorf("Base %d Mult %.2f Total %d; want 32 %.2f %d", result.Base, result.Mult, result.Total, plain.Mult*3, int(32*plain.Mult*3))
+	}
+	if result.Gold != 2 || ctx.Rocks != -1 {
+		t.Errorf("Gold %d Rocks %d; want 2 -1", result.Gold, ctx.Rocks)
+	}
+
+	var sources []string
+	for _, effect := range result.Effects {
+		sources = append(sources, effect.Source)
+	}
+	want := "head start,threes x2,threes x2,threes x2,threes x2,pairs x3"
+	if strings.Join(sources, ",") != want {
+		t.Errorf("effects %v; want %s", sources, want)
+	}
+
+	// same subscribers, same dice, same result
+	again, _ := pipeline.ScoreHand(scored, dice.TWO_PAIR, nil)
+	if !reflect.DeepEqual(result, again) {
+		t.Errorf("scoring again gave\n%v\nthen\n%v", result, again)
+	}
+
+	// without subscribers it is just the breakdown
+	if bare, _ := NewPipeline().ScoreHand(scored, dice.TWO_PAIR, nil); bare.Total != plain.Total || bare.Base != plain.Base {
+		t.Errorf("no subscribers Total %d Base %d; want %d %d", bare.Total, bare.Base, plain.Total, plain.Base)
+	}
+}
+
+func TestFireLevel(t *testing.T) {
+	pipeline := NewPipeline()
+	var got []string
+	for _, event := range []Event{OnLevelStarted, OnLevelCleared, OnGameOver, OnRunCleared} {
+		pipeline.Subscribe(event, RULE_ORDER, "log", func(ctx *Context) {
+			got = append(got, fmt.Sprintf("%s %d", ctx.Event, ctx.Level))
+			if ctx.Event == OnLevelStarted {
+				ctx.AddRolls(1)
+			}
+		})
+	}
+
+	ctx := pipeline.FireLevel(OnLevelStarted, 2)
+	pipeline.FireLevel(OnGameOver, 2)
+	if want := []string{"OnLevelStarted 2", "OnGameOver 2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fired %v; want %v", got, want)
+	}
+	if ctx.Rolls != 1 {
+		t.Errorf("OnLevelStarted rolls = %d; want 1", ctx.Rolls)
+	}
+}
@@ -0,0 +1,286 @@
+// Package gems is everything mined out of the rocks: gems, the garnets they break down into,
+// and garnets socketed into the pips of dice.
+//
+// gems and garnets share a Type and can both be cut, tumbled and polished. see parts.md
+package gems
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/ninesl/dice-will-roll/dice"
+)
+
+// Type is the kind of stone, shared between a gem and the garnets it breaks down into
+type Type uint8
+
+const (
+	Amethyst Type = iota
+	Topaz
+	Emerald
+	Ruby
+	Sapphire
+	Diamond
+	Onyx
+
+	NUM_TYPES // not a type. used for validation and iterating
+)
+
+// the base stats of every stone of a Type
+type typeInfo struct {
+	name      string
+	ability   dice.Modifier // what a garnet puts on a pip, and the gem's base ability
+	toughness int           // higher breaks less, see Stone.BreakChance
+	uses      int           // uses before a garnet always breaks
+	value     int           // gold
+}
+
+var typeTable = [NUM_TYPES]typeInfo{
+	Amethyst: {name: "Amethyst", ability: dice.ModWILD, toughness: 3, uses: 2, value: 8},
+	Topaz:    {name: "Topaz", ability: dice.ModGOLD, toughness: 6, uses: 4, value: 4},
+	Emerald:  {name: "Emerald", ability: dice.ModBONUS, toughness: 5, uses: 5, value: 3},
+	Ruby:     {name: "Ruby", ability: dice.ModMULT, toughness: 7, uses: 3, value: 6},
+	Sapphire: {name: "Sapphire", ability: dice.ModDOUBLE, toughness: 8, uses: 2, value: 7},
+	Diamond:  {name: "Diamond", ability: dice.ModBONUS_BIG, toughness: 10, uses: 6, value: 10},
+	Onyx:     {name: "Onyx", ability: dice.ModLOADED, toughness: 4, uses: 3, value: 5},
+}
+
+func (t Type) Valid() bool {
+	return t < NUM_TYPES
+}
+
+func (t Type) String() string {
+	if !t.Valid() {
+		return fmt.Sprintf("Type(%d)", uint8(t))
+	}
+	return typeTable[t].name
+}
+
+// the dice.Modifier a stone of this type gives
+func (t Type) Ability() dice.Modifier {
+	if !t.Valid() {
+		return dice.ModNONE
+	}
+	return typeTable[t].ability
+}
+
+// Cut is the shape a stone was cut into. every stone starts Rough and can only be cut once
+type Cut uint8
+
+const (
+	Rough Cut = iota
+	Cushion
+	Pear
+	Marquise
+
+	NUM_CUTS
+)
+
+// what a cut changes. 0 for any field means no change
+type cutInfo struct {
+	name      string
+	toughness int     // added to the type's toughness
+	uses      int     // added to the type's uses
+	value     float32 // multiplies the stone's value
+}
+
+var cutTable = [NUM_CUTS]cutInfo{
+	Rough:    {name: "Rough", value: 1},
+	Cushion:  {name: "Cushion", toughness: 2, value: 1.5}, // rounded, hard to chip
+	Pear:     {name: "Pear", uses: 1, value: 1.75},
+	Marquise: {name: "Marquise", toughness: -1, uses: 2, value: 2.5}, // pointy ends, fragile but lasts
+}
+
+func (c Cut) Valid() bool {
+	return c < NUM_CUTS
+}
+
+func (c Cut) String() string {
+	if !c.Valid() {
+		return fmt.Sprintf("Cut(%d)", uint8(c))
+	}
+	return cutTable[c].name
+}
+
+// modifications that aren't a cut. each can be done once
+const (
+	TUMBLE_TOUGHNESS = 1 // tumbling smooths out cracks
+	TUMBLE_VALUE     = 1.25
+	POLISH_USES      = 1 // polishing protects the surface
+	POLISH_VALUE     = 1.5
+)
+
+// lowest toughness any stone can have, no matter the cut
+const MIN_TOUGHNESS = 1
+
+// chance a stone breaks on a use is BREAK_CHANCE / toughness
+const BREAK_CHANCE = 0.5
+
+var (
+	ErrUnknownType     = errors.New("unknown gem type")
+	ErrUnknownCut      = errors.New("unknown cut")
+	ErrAlreadyCut      = errors.New("stone is already cut")
+	ErrAlreadyTumbled  = errors.New("stone is already tumbled")
+	ErrAlreadyPolished = errors.New("stone is already polished")
+)
+
+// Stone is what gems and garnets share
+type Stone struct {
+	Type     Type
+	Cut      Cut
+	Tumbled  bool
+	Polished bool
+}
+
+// "Polished Pear Ruby"
+func (s Stone) String() string {
+	name := s.Type.String()
+	if s.Cut != Rough {
+		name = s.Cut.String() + " " + name
+	}
+	if s.Polished {
+		name = "Polished " + name
+	}
+	if s.Tumbled {
+		name = "Tumbled " + name
+	}
+	return name
+}
+
+func (s Stone) validate() error {
+	if !s.Type.Valid() {
+		return fmt.Errorf("%w %d", ErrUnknownType, uint8(s.Type))
+	}
+	if !s.Cut.Valid() {
+		return fmt.Errorf("%w %d", ErrUnknownCut, uint8(s.Cut))
+	}
+	return nil
+}
+
+// the dice.Modifier the stone gives
+func (s Stone) Ability() dice.Modifier {
+	return s.Type.Ability()
+}
+
+// how hard the stone is to break. never below MIN_TOUGHNESS
+func (s Stone) Toughness() int {
+	toughness := typeTable[s.Type].toughness + cutTable[s.Cut].toughness
+	if s.Tumbled {
+		toughness += TUMBLE_TOUGHNESS
+	}
+	return max(toughness, MIN_TOUGHNESS)
+}
+
+// how many times the stone can be used before it always breaks
+func (s Stone) Uses() int {
+	uses := typeTable[s.Type].uses + cutTable[s.Cut].uses
+	if s.Polished {
+		uses += POLISH_USES
+	}
+	return max(uses, 1)
+}
+
+// the chance the stone breaks each time it is used, before running out of Uses
+func (s Stone) BreakChance() float64 {
+	return BREAK_CHANCE / float64(s.Toughness())
+}
+
+// what the stone sells for
+func (s Stone) Value() int {
+	value := float32(typeTable[s.Type].value) * cutTable[s.Cut].value
+	if s.Tumbled {
+		value *= TUMBLE_VALUE
+	}
+	if s.Polished {
+		value *= POLISH_VALUE
+	}
+	return int(value)
+}
+
+// cuts a Rough stone into cut
+func (s *Stone) CutInto(cut Cut) error {
+	if !cut.Valid() || cut == Rough {
+		return fmt.Errorf("%w %d", ErrUnknownCut, uint8(cut))
+	}
+	if s.Cut != Rough {
+		return fmt.Errorf("%w (%s)", ErrAlreadyCut, s.Cut)
+	}
+	s.Cut = cut
+	return nil
+}
+
+func (s *Stone) Tumble() error {
+	if s.Tumbled {
+		return ErrAlreadyTumbled
+	}
+	s.Tumbled = true
+	return nil
+}
+
+func (s *Stone) Polish() error {
+	if s.Polished {
+		return ErrAlreadyPolished
+	}
+	s.Polished = true
+	return nil
+}
+
+// true when a use breaks the stone. always true once used reaches Uses
+func (s Stone) breaks(used int, r *rand.Rand) bool {
+	if used >= s.Uses() {
+		return true
+	}
+	return r.Float64() < s.BreakChance()
+}
+
+// Gem is a whole stone, mined out of a rock. it can go in jewelry or be broken down into garnets
+type Gem struct {
+	Stone
+}
+
+// a Rough gem of t
+func NewGem(t Type) (Gem, error) {
+	gem := Gem{Stone{Type: t}}
+	if err := gem.validate(); err != nil {
+		return Gem{}, err
+	}
+	return gem, nil
+}
+
+// garnets a gem breaks down into. cut gems have already lost some of the stone
+const GARNETS_PER_GEM = 3
+
+// breaks the gem down into Rough garnets of its type, the gem is gone after
+func (g Gem) BreakDown() []Garnet {
+	count := GARNETS_PER_GEM
+	if g.Cut != Rough {
+		count--
+	}
+
+	garnets := make([]Garnet, count)
+	for i := range garnets {
+		garnets[i] = Garnet{Stone: Stone{Type: g.Type}}
+	}
+	return garnets
+}
+
+// Garnet is a small stone that gets socketed into a pip, see Sockets
+type Garnet struct {
+	Stone
+	Used int // times the garnet's pip was scored
+}
+
+// a Rough garnet of t, garnets usually come from Gem.BreakDown
+func NewGarnet(t Type) (Garnet, error) {
+	garnet := Garnet{Stone: Stone{Type: t}}
+	if err := garnet.validate(); err != nil {
+		return Garnet{}, err
+	}
+	return garnet, nil
+}
+
+// uses left before the garnet always breaks
+func (g Garnet) UsesLeft() int {
+	return max(g.Uses()-g.Used, 0)
+}
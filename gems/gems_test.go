@@ -0,0 +1,218 @@
+package gems
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
+	"github.com/ninesl/dice-will-roll/rng"
+)
+
+func TestStoneModifications(t *testing.T) {
+	gem, err := NewGem(Ruby)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rough := gem.Stone
+
+	if err := gem.CutInto(Cushion); err != nil {
+		t.Fatal(err)
+	}
+	if err := gem.CutInto(Pear); !errors.Is(err, ErrAlreadyCut) {
+		t.Errorf("cutting twice error = %v; want %v", err, ErrAlreadyCut)
+	}
+	if gem.Toughness() != rough.Toughness()+2 || gem.Value() <= rough.Value() {
+		t.Errorf("cushion cut toughness %d value %d; rough %d %d", gem.Toughness(), gem.Value(), rough.Toughness(), rough.Value())
+	}
+
+	if err := gem.Tumble(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gem.Tumble(); !errors.Is(err, ErrAlreadyTumbled) {
+		t.Errorf("tumbling twice error = %v; want %v", err, ErrAlreadyTumbled)
+	}
+	if err := gem.Polish(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gem.Polish(); !errors.Is(err, ErrAlreadyPolished) {
+		t.Errorf("polishing twice error = %v; want %v", err, ErrAlreadyPolished)
+	}
+	if gem.Uses() != rough.Uses()+POLISH_USES {
+		t.Errorf("polished uses %d; want %d", gem.Uses(), rough.Uses()+POLISH_USES)
+	}
+	if gem.String() != "Tumbled Polished Cushion Ruby" {
+		t.Errorf("String() = %q", gem.String())
+	}
+	if gem.Ability() != dice.ModMULT {
+		t.Errorf("Ability() = %s; want %s", gem.Ability(), dice.ModMULT)
+	}
+
+	// marquise is fragile, but never below MIN_TOUGHNESS
+	amethyst := Stone{Type: Amethyst, Cut: Marquise}
+	if amethyst.Toughness() != typeTable[Amethyst].toughness-1 {
+		t.Errorf("marquise amethyst toughness %d", amethyst.Toughness())
+	}
+	for typ := range NUM_TYPES {
+		for cut := range NUM_CUTS {
+			stone := Stone{Type: typ, Cut: cut}
+			if stone.Toughness() < MIN_TOUGHNESS || stone.Uses() < 1 {
+				t.Errorf("%s toughness %d uses %d", stone, stone.Toughness(), stone.Uses())
+			}
+		}
+	}
+
+	if _, err := NewGem(NUM_TYPES); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("NewGem(NUM_TYPES) error = %v; want %v", err, ErrUnknownType)
+	}
+	if err := (&Stone{}).CutInto(NUM_CUTS); !errors.Is(err, ErrUnknownCut) {
+		t.Errorf("CutInto(NUM_CUTS) error = %v; want %v", err, ErrUnknownCut)
+	}
+}
+
+func TestBreakDown(t *testing.T) {
+	gem, _ := NewGem(Topaz)
+	gem.Polish()
+	garnets := gem.BreakDown()
+	if len(garnets) != GARNETS_PER_GEM {
+		t.Fatalf("rough gem broke down into %d garnets; want %d", len(garnets), GARNETS_PER_GEM)
+	}
+	for _, garnet := range garnets {
+		if garnet.Stone != (Stone{Type: Topaz}) || garnet.Used != 0 {
+			t.Errorf("garnet %+v should be a fresh rough Topaz", garnet)
+		}
+	}
+
+	gem.CutInto(Pear)
+	if len(gem.BreakDown()) != GARNETS_PER_GEM-1 {
+		t.Errorf("cut gem broke down into %d garnets", len(gem.BreakDown()))
+	}
+}
+
+func TestSocket(t *testing.T) {
+	die := dice.New6SidedDie([6]int{1, 2, 3, 4, 5, 6})
+	die.SetModifier(2, 1, dice.ModBONUS)
+
+	var sockets Sockets
+	garnet, _ := NewGarnet(Sapphire)
+	if err := sockets.Socket(&die, 2, 1, garnet); err != nil {
+		t.Fatal(err)
+	}
+	if mod := die.Face(2).Modifiers()[1]; mod != dice.ModDOUBLE {
+		t.Errorf("socketed pip has %s; want %s", mod, dice.ModDOUBLE)
+	}
+	if err := sockets.Socket(&die, 2, 1, garnet); !errors.Is(err, ErrSocketTaken) {
+		t.Errorf("socketing twice error = %v; want %v", err, ErrSocketTaken)
+	}
+	if err := sockets.Socket(&die, 0, 1, garnet); !errors.Is(err, dice.ErrInvalidPip) {
+		t.Errorf("socketing a missing pip error = %v; want %v", err, dice.ErrInvalidPip)
+	}
+	if got, ok := sockets.At(&die, 2, 1); !ok || got.Type != Sapphire {
+		t.Errorf("At() = %v, %v", got, ok)
+	}
+
+	got, err := sockets.Unsocket(&die, 2, 1)
+	if err != nil || got.Type != Sapphire {
+		t.Fatalf("Unsocket() = %v, %v", got, err)
+	}
+	if mod := die.Face(2).Modifiers()[1]; mod != dice.ModBONUS {
+		t.Errorf("unsocketed pip has %s; want its old %s back", mod, dice.ModBONUS)
+	}
+	if _, err := sockets.Unsocket(&die, 2, 1); !errors.Is(err, ErrNoSocket) {
+		t.Errorf("Unsocket() twice error = %v; want %v", err, ErrNoSocket)
+	}
+
+	// a die rebuilt under its garnet has no pip to restore, the garnet still comes out
+	sockets.Socket(&die, 2, 1, garnet)
+	die = dice.New6SidedDie([6]int{1, 1, 1, 1, 1, 1})
+	if _, err := sockets.Unsocket(&die, 2, 1); !errors.Is(err, dice.ErrInvalidPip) {
+		t.Errorf("Unsocket() from a rebuilt die error = %v; want %v", err, dice.ErrInvalidPip)
+	}
+	if _, ok := sockets.At(&die, 2, 1); ok {
+		t.Errorf("garnet is still socketed after it couldn't be restored")
+	}
+}
+
+func TestGarnetsBreak(t *testing.T) {
+	streams := rng.New(5)
+	die := dice.New6SidedDie([6]int{3, 3, 3, 3, 3, 3})
+	other := dice.New6SidedDie([6]int{3, 3, 3, 3, 3, 3})
+
+	var sockets Sockets
+	diamond, _ := NewGarnet(Diamond)
+	amethyst, _ := NewGarnet(Amethyst)
+	sockets.Socket(&die, 0, 0, diamond)
+	sockets.Socket(&die, 0, 2, amethyst)
+	sockets.Socket(&die, 1, 0, amethyst)
+	sockets.Socket(&other, 0, 0, amethyst)
+
+	var broke []BreakEvent
+	for range diamond.Uses() {
+		events, err := sockets.Scored(&die, 0, streams.Rocks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		broke = append(broke, events...)
+	}
+
+	// every garnet on the face ran out of uses, nothing else was touched
+	if len(broke) != 2 {
+		t.Fatalf("%d garnets broke; want 2", len(broke))
+	}
+	for _, event := range broke {
+		if event.Die != &die || event.Face != 0 || event.Restored != dice.ModNONE {
+			t.Errorf("unexpected break %+v", event)
+		}
+		if mod := die.Face(0).Modifiers()[event.Pip]; mod != dice.ModNONE {
+			t.Errorf("broken garnet left %s on pip %d", mod, event.Pip)
+		}
+	}
+	if len(sockets.OnDie(&die)) != 1 || len(sockets.OnDie(&other)) != 1 {
+		t.Errorf("garnets on other faces and dice should not be used")
+	}
+
+	if events := sockets.Breaks(); len(events) != 2 {
+		t.Errorf("Breaks() = %d events; want 2", len(events))
+	}
+	if events := sockets.Breaks(); len(events) != 0 {
+		t.Errorf("Breaks() should be drained, got %d events", len(events))
+	}
+}
+
+func TestSocketsSubscribe(t *testing.T) {
+	die := dice.New6SidedDie([6]int{4, 4, 4, 4, 4, 4})
+	var sockets Sockets
+	emerald, _ := NewGarnet(Emerald)
+	sockets.Socket(&die, 0, 0, emerald)
+
+	pipeline := events.NewPipeline()
+	sockets.Subscribe(pipeline, rng.New(3).Rocks)
+
+	for range emerald.Uses() {
+		pipeline.ScoreHand([]*dice.Die{&die}, dice.HIGH_DIE, nil)
+	}
+	if len(sockets.OnDie(&die)) != 0 || len(sockets.Breaks()) != 1 {
+		t.Errorf("garnet should have broken after %d scored hands", emerald.Uses())
+	}
+}
+
+// garnets break early about as often as their toughness says
+func TestBreakChance(t *testing.T) {
+	const TRIES = 20000
+	streams := rng.New(11)
+
+	for _, typ := range []Type{Amethyst, Diamond} {
+		stone := Stone{Type: typ, Cut: Pear} // enough uses that the first never runs out
+		var broke int
+		for range TRIES {
+			if stone.breaks(1, streams.Rocks) {
+				broke++
+			}
+		}
+
+		got, want := float64(broke)/TRIES, stone.BreakChance()
+		if got < want*0.9 || got > want*1.1 {
+			t.Errorf("%s broke %.3f of the time; want about %.3f", typ, got, want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package gems
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
+)
+
+// Jewelry is made by the smith and equipped into a run's JewelrySlots.
+// it is the run-wide passive buff, every piece has one or more Effects that trigger while hands are scored
+type Jewelry struct {
+	Name    string
+	Kind    JewelryKind
+	Effects []Effect
+}
+
+type JewelryKind uint8
+
+const (
+	Ring JewelryKind = iota
+	Necklace
+	Bracelet
+	Earring
+
+	NUM_JEWELRY_KINDS
+)
+
+var jewelryKindStringMap = map[JewelryKind]string{
+	Ring:     "Ring",
+	Necklace: "Necklace",
+	Bracelet: "Bracelet",
+	Earring:  "Earring",
+}
+
+func (k JewelryKind) String() string {
+	if name, ok := jewelryKindStringMap[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("JewelryKind(%d)", uint8(k))
+}
+
+// "Ruby Ring: +2 per die showing 2, x1.50 if hand is a straight"
+func (j Jewelry) String() string {
+	effects := make([]string, len(j.Effects))
+	for i, effect := range j.Effects {
+		effects[i] = effect.String()
+	}
+	return fmt.Sprintf("%s: %s", j.Name, strings.Join(effects, ", "))
+}
+
+// EffectKind is what an Effect does and when it triggers
+type EffectKind uint8
+
+const (
+	FlatPerValue EffectKind = iota // +Amount to every scored die counting as Value
+	MultPerValue                   // xAmount to every scored die counting as Value
+	GoldPerValue                   // +Amount gold for every scored die counting as Value
+	FlatIfHand                     // +Amount to the hand's base when the hand is one of Hands
+	MultIfHand                     // xAmount to the hand's mult when the hand is one of Hands
+
+	NUM_EFFECT_KINDS
+)
+
+// Effect is one buff of a piece of Jewelry
+//
+//	Effect{Kind: FlatPerValue, Value: 2, Amount: 2}                     // +2 per die showing 2
+//	Effect{Kind: MultIfHand, Hands: dice.StraightHands, Amount: 1.5}  // x1.5 if hand is a straight
+type Effect struct {
+	Kind   EffectKind
+	Value  int             // the die value for the PerValue kinds
+	Hands  []dice.HandRank // the hands for the IfHand kinds
+	Amount float32
+}
+
+func (e Effect) Moment() dice.ScoreMoment {
+	switch e.Kind {
+	case FlatIfHand, MultIfHand:
+		return dice.OnHandScored
+	default:
+		return dice.OnDieScored
+	}
+}
+
+// what the effect does to die, or the hand when die is nil
+func (e Effect) Trigger(result *dice.ScoreResult, die *dice.DieScore) (dice.EffectScore, bool) {
+	switch e.Kind {
+	case FlatPerValue, MultPerValue, GoldPerValue:
+		if die == nil || die.Value != e.Value {
+			return dice.EffectScore{}, false
+		}
+	case FlatIfHand, MultIfHand:
+		if !slices.Contains(e.Hands, result.Hand) {
+			return dice.EffectScore{}, false
+		}
+	default:
+		return dice.EffectScore{}, false
+	}
+
+	var score dice.EffectScore
+	switch e.Kind {
+	case FlatPerValue, FlatIfHand:
+		score.Added = e.Amount
+	case MultPerValue, MultIfHand:
+		score.Mult = e.Amount
+	case GoldPerValue:
+		score.Gold = int(e.Amount)
+	}
+	return score, true
+}
+
+func (e Effect) String() string {
+	switch e.Kind {
+	case FlatPerValue:
+		return fmt.Sprintf("%+g per die showing %d", e.Amount, e.Value)
+	case MultPerValue:
+		return fmt.Sprintf("x%g per die showing %d", e.Amount, e.Value)
+	case GoldPerValue:
+		return fmt.Sprintf("%+g gold per die showing %d", e.Amount, e.Value)
+	case FlatIfHand:
+		return fmt.Sprintf("%+g if hand is %s", e.Amount, handsString(e.Hands))
+	case MultIfHand:
+		return fmt.Sprintf("x%g if hand is %s", e.Amount, handsString(e.Hands))
+	}
+	return fmt.Sprintf("EffectKind(%d)", uint8(e.Kind))
+}
+
+func handsString(hands []dice.HandRank) string {
+	if slices.Equal(hands, dice.StraightHands) {
+		return "a straight"
+	}
+	names := make([]string, len(hands))
+	for i := range hands {
+		names[i] = hands[i].String()
+	}
+	return strings.Join(names, " or ")
+}
+
+// how many pieces of jewelry a run can wear at once
+const JEWELRY_SLOTS = 5
+
+var (
+	ErrInvalidSlot = errors.New("no such jewelry slot")
+	ErrSlotTaken   = errors.New("jewelry slot is taken")
+	ErrSlotEmpty   = errors.New("jewelry slot is empty")
+)
+
+// JewelrySlots is the jewelry a run has equipped. effects resolve in slot order, see Effects
+type JewelrySlots struct {
+	slots [JEWELRY_SLOTS]*Jewelry
+}
+
+func validSlot(slot int) error {
+	if slot < 0 || slot >= JEWELRY_SLOTS {
+		return fmt.Errorf("%w %d, there are %d", ErrInvalidSlot, slot, JEWELRY_SLOTS)
+	}
+	return nil
+}
+
+// puts jewelry in an empty slot
+func (s *JewelrySlots) Equip(slot int, jewelry Jewelry) error {
+	if err := validSlot(slot); err != nil {
+		return err
+	}
+	if s.slots[slot] != nil {
+		return fmt.Errorf("%w by %s", ErrSlotTaken, s.slots[slot].Name)
+	}
+	s.slots[slot] = &jewelry
+	return nil
+}
+
+// takes the jewelry out of a slot
+func (s *JewelrySlots) Unequip(slot int) (Jewelry, error) {
+	if err := validSlot(slot); err != nil {
+		return Jewelry{}, err
+	}
+	if s.slots[slot] == nil {
+		return Jewelry{}, fmt.Errorf("%w %d", ErrSlotEmpty, slot)
+	}
+	jewelry := *s.slots[slot]
+	s.slots[slot] = nil
+	return jewelry, nil
+}
+
+// the jewelry in a slot, nil when it is empty
+func (s *JewelrySlots) Slot(slot int) *Jewelry {
+	if validSlot(slot) != nil {
+		return nil
+	}
+	return s.slots[slot]
+}
+
+// an Effect of the jewelry in a slot, so the breakdown shows where it came from
+type slotEffect struct {
+	Effect
+	slot int
+	name string
+}
+
+func (e slotEffect) Trigger(result *dice.ScoreResult, die *dice.DieScore) (dice.EffectScore, bool) {
+	score, ok := e.Effect.Trigger(result, die)
+	score.Source = e.name
+	score.Slot = e.slot
+	return score, ok
+}
+
+// every effect of every equipped piece, first slot first
+func (s *JewelrySlots) Effects() []dice.ScoreEffect {
+	var effects []dice.ScoreEffect
+	for slot, jewelry := range s.slots {
+		if jewelry == nil {
+			continue
+		}
+		for _, effect := range jewelry.Effects {
+			effects = append(effects, slotEffect{Effect: effect, slot: slot, name: jewelry.Name})
+		}
+	}
+	return effects
+}
+
+// runs the effects of whatever is equipped when each hand is scored, in slot order
+func (s *JewelrySlots) Subscribe(pipeline *events.Pipeline) []events.Subscription {
+	trigger := func(moment dice.ScoreMoment) events.Handler {
+		return func(ctx *events.Context) {
+			for _, effect := range s.Effects() {
+				if effect.Moment() != moment {
+					continue
+				}
+				if score, ok := effect.Trigger(ctx.Result, ctx.Die); ok {
+					ctx.Apply(score)
+				}
+			}
+		}
+	}
+
+	return []events.Subscription{
+		pipeline.Subscribe(events.OnDieScored, events.JEWELRY_ORDER, "Jewelry", trigger(dice.OnDieScored)),
+		pipeline.Subscribe(events.AfterHandScored, events.JEWELRY_ORDER, "Jewelry", trigger(dice.OnHandScored)),
+	}
+}
@@ -0,0 +1,157 @@
+package gems
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
+)
+
+// dice that show value whichever way they land
+func showing(values ...int) []dice.Die {
+	var showing []dice.Die
+	for _, value := range values {
+		showing = append(showing, dice.New6SidedDie([6]int{value, value, value, value, value, value}))
+	}
+	return showing
+}
+
+// scores values as hand with slots subscribed, the way a run scores it
+func scoreWith(slots *JewelrySlots, hand dice.HandRank, values ...int) dice.ScoreResult {
+	pipeline := events.NewPipeline()
+	slots.Subscribe(pipeline)
+
+	shown := showing(values...)
+	scored := make([]*dice.Die, len(shown))
+	for i := range shown {
+		scored[i] = &shown[i]
+	}
+	result, _ := pipeline.ScoreHand(scored, hand, nil)
+	return result
+}
+
+func TestJewelrySlots(t *testing.T) {
+	var slots JewelrySlots
+	ring := Jewelry{Name: "Ruby Ring", Kind: Ring, Effects: []Effect{{Kind: FlatPerValue, Value: 2, Amount: 2}}}
+
+	if err := slots.Equip(0, ring); err != nil {
+		t.Fatal(err)
+	}
+	if err := slots.Equip(0, ring); !errors.Is(err, ErrSlotTaken) {
+		t.Errorf("Equip() on a taken slot error = %v; want %v", err, ErrSlotTaken)
+	}
+	if err := slots.Equip(JEWELRY_SLOTS, ring); !errors.Is(err, ErrInvalidSlot) {
+		t.Errorf("Equip(%d) error = %v; want %v", JEWELRY_SLOTS, err, ErrInvalidSlot)
+	}
+	if slots.Slot(0) == nil || slots.Slot(0).Name != "Ruby Ring" || slots.Slot(1) != nil {
+		t.Errorf("Slot(0) = %v, Slot(1) = %v", slots.Slot(0), slots.Slot(1))
+	}
+
+	got, err := slots.Unequip(0)
+	if err != nil || got.Name != "Ruby Ring" {
+		t.Fatalf("Unequip() = %v, %v", got, err)
+	}
+	if _, err := slots.Unequip(0); !errors.Is(err, ErrSlotEmpty) {
+		t.Errorf("Unequip() twice error = %v; want %v", err, ErrSlotEmpty)
+	}
+}
+
+func TestJewelryScoring(t *testing.T) {
+	var slots JewelrySlots
+	slots.Equip(3, Jewelry{Name: "Straight Necklace", Kind: Necklace, Effects: []Effect{
+		{Kind: MultIfHand, Hands: dice.StraightHands, Amount: 1.5},
+	}})
+	slots.Equip(1, Jewelry{Name: "Two Ring", Kind: Ring, Effects: []Effect{
+		{Kind: FlatPerValue, Value: 2, Amount: 2},
+		{Kind: GoldPerValue, Value: 2, Amount: 1},
+	}})
+	slots.Equip(2, Jewelry{Name: "Pair Earring", Kind: Earring, Effects: []Effect{
+		{Kind: FlatIfHand, Hands: []dice.HandRank{dice.ONE_PAIR}, Amount: 10},
+	}})
+
+	plain := dice.ScoreHandBreakdown(showing(1, 2, 3, 4), dice.STRAIGHT_SMALL, nil)
+	base, mult := plain.Base, plain.Mult
+	straight := scoreWith(&slots, dice.STRAIGHT_SMALL, 1, 2, 3, 4)
+
+	if straight.Base != base+2 || straight.Mult != mult*1.5 || straight.Gold != 1 {
+		t.Errorf("straight Base %d Mult %.2f Gold %d; want %d %.2f 1", straight.Base, straight.Mult, straight.Gold, base+2, mult*1.5)
+	}
+	if straight.Total != int(float32(base+2)*mult*1.5) {
+		t.Errorf("straight Total = %d", straight.Total)
+	}
+
+	// the Two Ring is in slot 1, so it resolves before the necklace in slot 3
+	var sources []string
+	for _, effect := range straight.Effects {
+		sources = append(sources, effect.Source)
+	}
+	if strings.Join(sources, ",") != "Two Ring,Two Ring,Straight Necklace" {
+		t.Errorf("effects resolved in order %v", sources)
+	}
+	if straight.Effects[0].Slot != 1 || straight.Effects[2].Slot != 3 {
+		t.Errorf("effect slots %d %d; want 1 3", straight.Effects[0].Slot, straight.Effects[2].Slot)
+	}
+	if !strings.Contains(straight.String(), "+2.00 Two Ring") || !strings.Contains(straight.String(), "x1.50 Straight Necklace") {
+		t.Errorf("breakdown should show every effect\n%s", straight.String())
+	}
+
+	// no 2s and not a straight, only the earring triggers
+	base = dice.ScoreHandBreakdown(showing(5, 5, 1), dice.ONE_PAIR, nil).Base
+	pair := scoreWith(&slots, dice.ONE_PAIR, 5, 5, 1)
+	if len(pair.Effects) != 1 || pair.Effects[0].Source != "Pair Earring" || pair.Base != base+10 {
+		t.Errorf("pair effects %v base %d; want only Pair Earring and %d", pair.Effects, pair.Base, base+10)
+	}
+}
+
+func TestEffectString(t *testing.T) {
+	tests := []struct {
+		effect Effect
+		want   string
+	}{
+		{Effect{Kind: FlatPerValue, Value: 2, Amount: 2}, "+2 per die showing 2"},
+		{Effect{Kind: MultIfHand, Hands: dice.StraightHands, Amount: 1.5}, "x1.5 if hand is a straight"},
+		{Effect{Kind: FlatIfHand, Hands: []dice.HandRank{dice.ONE_PAIR, dice.TWO_PAIR}, Amount: 4}, "+4 if hand is One Pair or Two Pair"},
+	}
+	for _, tc := range tests {
+		if got := tc.effect.String(); got != tc.want {
+			t.Errorf("String() = %q; want %q", got, tc.want)
+		}
+	}
+}
+
+// subscribed jewelry scores whatever is equipped when the hand is scored
+func TestJewelrySubscribe(t *testing.T) {
+	var slots JewelrySlots
+	slots.Equip(0, Jewelry{Name: "Two Ring", Kind: Ring, Effects: []Effect{{Kind: FlatPerValue, Value: 2, Amount: 2}}})
+
+	pipeline := events.NewPipeline()
+	subs := slots.Subscribe(pipeline)
+
+	hand := showing(1, 2, 3, 4)
+	scored := make([]*dice.Die, len(hand))
+	for i := range hand {
+		scored[i] = &hand[i]
+	}
+	want := dice.ScoreHandBreakdown(hand, dice.STRAIGHT_SMALL, nil)
+
+	if got, _ := pipeline.ScoreHand(scored, dice.STRAIGHT_SMALL, nil); got.Base != want.Base+2 {
+		t.Errorf("Base %d; want %d", got.Base, want.Base+2)
+	}
+
+	// equipping later is picked up by the next hand
+	slots.Equip(2, Jewelry{Name: "Big Earring", Kind: Earring, Effects: []Effect{
+		{Kind: FlatIfHand, Hands: dice.StraightHands, Amount: 5},
+	}})
+	if again, _ := pipeline.ScoreHand(scored, dice.STRAIGHT_SMALL, nil); again.Base != want.Base+7 {
+		t.Errorf("Base %d after equipping; want %d", again.Base, want.Base+7)
+	}
+
+	for _, sub := range subs {
+		pipeline.Unsubscribe(sub)
+	}
+	if got, _ := pipeline.ScoreHand(scored, dice.STRAIGHT_SMALL, nil); got.Base != want.Base || len(got.Effects) != 0 {
+		t.Errorf("unsubscribed jewelry still scored\n%v", got)
+	}
+}
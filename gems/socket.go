@@ -0,0 +1,168 @@
+package gems
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
+)
+
+var (
+	ErrSocketTaken = errors.New("pip already has a garnet")
+	ErrNoSocket    = errors.New("pip has no garnet")
+)
+
+// Socket is a garnet sitting in a pip. the pip carries the garnet's Ability
+// until it breaks, then gets back the Modifier it had before
+type Socket struct {
+	Die    *dice.Die
+	Face   int
+	Pip    int
+	Garnet Garnet
+
+	replaced dice.Modifier // the pip's Modifier before the garnet went in
+}
+
+// BreakEvent is a garnet that broke and fell off its pip. the renderer plays the falling off animation from it
+type BreakEvent struct {
+	Socket
+	Restored dice.Modifier // the Modifier the pip has now
+}
+
+// Sockets tracks every garnet socketed into a run's dice.
+//
+// when a face is scored, call Scored. garnets that break are taken off
+// and queued as BreakEvents until the renderer calls Breaks
+type Sockets struct {
+	sockets []Socket
+	breaks  []BreakEvent
+}
+
+func (s *Sockets) find(die *dice.Die, face, pip int) int {
+	for i, socket := range s.sockets {
+		if socket.Die == die && socket.Face == face && socket.Pip == pip {
+			return i
+		}
+	}
+	return -1
+}
+
+// puts garnet in a pip, replacing the pip's Modifier with garnet.Ability()
+func (s *Sockets) Socket(die *dice.Die, face, pip int, garnet Garnet) error {
+	if err := garnet.validate(); err != nil {
+		return err
+	}
+	if s.find(die, face, pip) != -1 {
+		return fmt.Errorf("%w (face %d pip %d)", ErrSocketTaken, face, pip)
+	}
+
+	edit, err := die.SetModifier(face, pip, garnet.Ability())
+	if err != nil {
+		return err
+	}
+
+	s.sockets = append(s.sockets, Socket{
+		Die:      die,
+		Face:     face,
+		Pip:      pip,
+		Garnet:   garnet,
+		replaced: edit.Before[0].Modifiers()[pip],
+	})
+	return nil
+}
+
+// takes the garnet out of a pip without breaking it, the pip gets its old Modifier back
+func (s *Sockets) Unsocket(die *dice.Die, face, pip int) (Garnet, error) {
+	i := s.find(die, face, pip)
+	if i == -1 {
+		return Garnet{}, fmt.Errorf("%w (face %d pip %d)", ErrNoSocket, face, pip)
+	}
+
+	socket, err := s.remove(i)
+	return socket.Garnet, err
+}
+
+// takes socket i off its die and restores the pip.
+//
+// the pip was checked when the garnet went in, restoring it only fails if the die was rebuilt under it.
+// the socket is gone either way, there is no pip left to hold it
+func (s *Sockets) remove(i int) (Socket, error) {
+	socket := s.sockets[i]
+	s.sockets = append(s.sockets[:i], s.sockets[i+1:]...)
+	if _, err := socket.Die.SetModifier(socket.Face, socket.Pip, socket.replaced); err != nil {
+		return socket, fmt.Errorf("restoring face %d pip %d under %s: %w", socket.Face, socket.Pip, socket.Garnet, err)
+	}
+	return socket, nil
+}
+
+// the garnet in a pip
+func (s *Sockets) At(die *dice.Die, face, pip int) (Garnet, bool) {
+	if i := s.find(die, face, pip); i != -1 {
+		return s.sockets[i].Garnet, true
+	}
+	return Garnet{}, false
+}
+
+// every garnet on a die
+func (s *Sockets) OnDie(die *dice.Die) []Socket {
+	var sockets []Socket
+	for _, socket := range s.sockets {
+		if socket.Die == die {
+			sockets = append(sockets, socket)
+		}
+	}
+	return sockets
+}
+
+// uses every garnet on a scored face. r should be a run stream so a seed always breaks the same garnets.
+//
+// returns the garnets that broke, they are also queued for Breaks. a garnet whose pip couldn't be restored
+// still breaks, its error is joined into the returned error
+func (s *Sockets) Scored(die *dice.Die, face int, r *rand.Rand) ([]BreakEvent, error) {
+	var (
+		broke []BreakEvent
+		errs  []error
+	)
+	for i := 0; i < len(s.sockets); i++ {
+		socket := &s.sockets[i]
+		if socket.Die != die || socket.Face != face {
+			continue
+		}
+
+		socket.Garnet.Used++
+		if !socket.Garnet.breaks(socket.Garnet.Used, r) {
+			continue
+		}
+
+		removed, err := s.remove(i)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		broke = append(broke, BreakEvent{Socket: removed, Restored: removed.replaced})
+		i--
+	}
+
+	s.breaks = append(s.breaks, broke...)
+	return broke, errors.Join(errs...)
+}
+
+// wears the garnets of every die scored through pipeline. r should be a run stream.
+//
+// a pip that can't be restored means a die was rebuilt without unsocketing it first, that panics
+func (s *Sockets) Subscribe(pipeline *events.Pipeline, r *rand.Rand) events.Subscription {
+	return pipeline.Subscribe(events.OnDieScored, events.GEM_ORDER, "Garnets", func(ctx *events.Context) {
+		die := ctx.Dice[ctx.Die.Index]
+		if _, err := s.Scored(die, die.ActiveFaceIndex(), r); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// every BreakEvent since the last call, oldest first. the renderer calls it each frame
+func (s *Sockets) Breaks() []BreakEvent {
+	breaks := s.breaks
+	s.breaks = nil
+	return breaks
+}
@@ -0,0 +1,176 @@
+// Package gold is the run's money. every change to the balance goes through a Ledger with a Reason,
+// so the end of a level can itemize where the gold came from and where it went.
+//
+//	ledger := &gold.Ledger{}
+//	ledger.Score(result, "Full House")
+//	ledger.LevelCleared(handsLeft, rollsLeft)
+//	for _, line := range ledger.Summary(level) { ... }
+package gold
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ninesl/dice-will-roll/dice"
+)
+
+// Reason is why the balance changed
+type Reason uint8
+
+const (
+	Rocks     Reason = iota // rocks broken by a hand, see RockGold
+	HandBonus               // the hand itself, see HandBonusGold
+	Modifiers               // gold pips (dice.ModGOLD), gems, jewelry and rules while a hand scored
+	Interest                // savings at the end of a level, see InterestGold
+	HandsLeft               // hands not played when a level was cleared
+	RollsLeft               // rolls not used on the last hand of a level
+	Bought                  // spent at Rolland
+	Sold                    // sold to Rolland
+	Reroll                  // Rolland's shelf was rerolled
+
+	NUM_REASONS // not a reason. used for validation and iterating
+)
+
+var reasonStringMap = map[Reason]string{
+	Rocks:     "Rocks",
+	HandBonus: "Hand Bonus",
+	Modifiers: "Modifiers",
+	Interest:  "Interest",
+	HandsLeft: "Hands Left",
+	RollsLeft: "Rolls Left",
+	Bought:    "Bought",
+	Sold:      "Sold",
+	Reroll:    "Reroll",
+}
+
+func (r Reason) String() string {
+	if name, ok := reasonStringMap[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Reason(%d)", uint8(r))
+}
+
+// tunables for how much gold the run pays out
+var (
+	ROCKS_PER_GOLD     = 25  // 1 gold for every this many rocks a hand breaks
+	HAND_BONUS_MULT    = 2.0 // 1 gold for every this much of the hand's Mult above x1
+	GOLD_PER_HAND_LEFT = 2
+	GOLD_PER_ROLL_LEFT = 1
+	SAVINGS_PER_GOLD   = 5 // 1 gold of interest for every this many saved
+	MAX_INTEREST       = 5
+)
+
+var ErrNotEnoughGold = errors.New("not enough gold")
+
+// Entry is one change to the balance
+type Entry struct {
+	Reason Reason
+	Amount int    // negative when gold was spent
+	Level  int    // index of the run's level it happened in
+	Note   string // what it was for, "Full House" "Rare d10"
+}
+
+// "Hand Bonus +2 (Full House)"
+func (e Entry) String() string {
+	if e.Note == "" {
+		return fmt.Sprintf("%s %+d", e.Reason, e.Amount)
+	}
+	return fmt.Sprintf("%s %+d (%s)", e.Reason, e.Amount, e.Note)
+}
+
+// Ledger is the run's gold. the balance only changes through Earn and Spend
+type Ledger struct {
+	Entries []Entry // every change, oldest first
+	Level   int     // the level new entries are recorded for, the run moves it along
+
+	balance int
+}
+
+func (l *Ledger) Balance() int {
+	return l.balance
+}
+
+// adds amount for reason. nothing is recorded for 0 or less
+func (l *Ledger) Earn(reason Reason, amount int, note string) {
+	if amount <= 0 {
+		return
+	}
+	l.balance += amount
+	l.Entries = append(l.Entries, Entry{Reason: reason, Amount: amount, Level: l.Level, Note: note})
+}
+
+// takes amount for reason, ErrNotEnoughGold when the balance is short and nothing changes
+func (l *Ledger) Spend(reason Reason, amount int, note string) error {
+	if amount <= 0 {
+		return nil
+	}
+	if l.balance < amount {
+		return fmt.Errorf("%w for %s, have %d need %d", ErrNotEnoughGold, note, l.balance, amount)
+	}
+	l.balance -= amount
+	l.Entries = append(l.Entries, Entry{Reason: reason, Amount: -amount, Level: l.Level, Note: note})
+	return nil
+}
+
+// gold for breaking rocks
+func RockGold(rocks int) int {
+	return max(rocks, 0) / ROCKS_PER_GOLD
+}
+
+// gold for scoring a hand with mult
+func HandBonusGold(mult float32) int {
+	return max(int(float64(mult-1)/HAND_BONUS_MULT), 0)
+}
+
+// interest on savings, never more than MAX_INTEREST
+func InterestGold(savings int) int {
+	return min(max(savings, 0)/SAVINGS_PER_GOLD, MAX_INTEREST)
+}
+
+// records everything a scored hand earned. hand is the note, the hand's name
+func (l *Ledger) Score(result dice.ScoreResult, hand string) {
+	l.Earn(Rocks, RockGold(result.Total), hand)
+	l.Earn(HandBonus, HandBonusGold(result.Mult), hand)
+	l.Earn(Modifiers, result.Gold, hand)
+}
+
+// pays out the end of a level. interest is worked out on the savings before the leftover hands and rolls are paid
+func (l *Ledger) LevelCleared(handsLeft, rollsLeft int) {
+	l.Earn(Interest, InterestGold(l.balance), fmt.Sprintf("%d saved", l.balance))
+	l.Earn(HandsLeft, handsLeft*GOLD_PER_HAND_LEFT, fmt.Sprintf("%d hands", handsLeft))
+	l.Earn(RollsLeft, rollsLeft*GOLD_PER_ROLL_LEFT, fmt.Sprintf("%d rolls", rollsLeft))
+}
+
+// Line is every entry of one Reason in a level added up
+type Line struct {
+	Reason  Reason
+	Amount  int
+	Entries int
+}
+
+// "Rocks +12 x3"
+func (l Line) String() string {
+	return fmt.Sprintf("%s %+d x%d", l.Reason, l.Amount, l.Entries)
+}
+
+// the end-of-level summary. the level's entries added up by Reason, in Reason order
+func (l *Ledger) Summary(level int) []Line {
+	var totals [NUM_REASONS]Line
+	for _, entry := range l.Entries {
+		if entry.Level != level || entry.Reason >= NUM_REASONS {
+			continue
+		}
+		totals[entry.Reason].Amount += entry.Amount
+		totals[entry.Reason].Entries++
+	}
+
+	var lines []Line
+	for reason, line := range totals {
+		if line.Entries == 0 {
+			continue
+		}
+		line.Reason = Reason(reason)
+		lines = append(lines, line)
+	}
+	return lines
+}
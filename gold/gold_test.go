@@ -0,0 +1,76 @@
+package gold
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ninesl/dice-will-roll/dice"
+)
+
+func TestEarnAndSpend(t *testing.T) {
+	var ledger Ledger
+	ledger.Earn(Sold, 10, "Ruby")
+	ledger.Earn(Rocks, 0, "nothing")
+
+	if err := ledger.Spend(Bought, 15, "Rare d10"); !errors.Is(err, ErrNotEnoughGold) {
+		t.Errorf("Spend() past the balance error = %v; want %v", err, ErrNotEnoughGold)
+	}
+	if err := ledger.Spend(Reroll, 4, "reroll"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ledger.Balance() != 6 {
+		t.Errorf("Balance() = %d; want 6", ledger.Balance())
+	}
+	want := []Entry{{Reason: Sold, Amount: 10, Note: "Ruby"}, {Reason: Reroll, Amount: -4, Note: "reroll"}}
+	if !reflect.DeepEqual(ledger.Entries, want) {
+		t.Errorf("Entries = %v; want %v", ledger.Entries, want)
+	}
+}
+
+func TestPayouts(t *testing.T) {
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"rocks", RockGold(ROCKS_PER_GOLD*3 + 1), 3},
+		{"no rocks", RockGold(-10), 0},
+		{"high die", HandBonusGold(1), 0},
+		{"full house", HandBonusGold(5), 2},
+		{"interest", InterestGold(SAVINGS_PER_GOLD * 2), 2},
+		{"interest cap", InterestGold(SAVINGS_PER_GOLD * (MAX_INTEREST + 10)), MAX_INTEREST},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s = %d; want %d", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestLevelSummary(t *testing.T) {
+	var ledger Ledger
+	ledger.Score(dice.ScoreResult{Total: ROCKS_PER_GOLD * 4, Mult: 5, Gold: 1}, "Full House")
+	ledger.Score(dice.ScoreResult{Total: ROCKS_PER_GOLD * 2, Mult: 1}, "High Die")
+	ledger.LevelCleared(3, 1)
+
+	ledger.Level = 1
+	ledger.Score(dice.ScoreResult{Total: ROCKS_PER_GOLD}, "High Die")
+
+	// 4+2 rocks, 2 bonus, 1 modifier = 9 saved before the level was cleared
+	want := []Line{
+		{Reason: Rocks, Amount: 6, Entries: 2},
+		{Reason: HandBonus, Amount: 2, Entries: 1},
+		{Reason: Modifiers, Amount: 1, Entries: 1},
+		{Reason: Interest, Amount: InterestGold(9), Entries: 1},
+		{Reason: HandsLeft, Amount: 3 * GOLD_PER_HAND_LEFT, Entries: 1},
+		{Reason: RollsLeft, Amount: GOLD_PER_ROLL_LEFT, Entries: 1},
+	}
+	if got := ledger.Summary(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summary(0) = %v; want %v", got, want)
+	}
+	if got := ledger.Summary(1); len(got) != 1 || got[0].Amount != 1 {
+		t.Errorf("Summary(1) = %v; want one line of Rocks +1", got)
+	}
+}
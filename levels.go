@@ -6,6 +6,8 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
+	"github.com/ninesl/dice-will-roll/gold"
 	"github.com/ninesl/dice-will-roll/music"
 	"github.com/ninesl/dice-will-roll/render"
 	"github.com/ninesl/dice-will-roll/rocks"
@@ -30,6 +32,9 @@ type Level struct {
 	RollsLeft    int    // rolls left this hand
 	MaxHands     int    // max hands this level
 	HandsLeft    int    // hands remaining this level
+	RollsUnused  int    // rolls that were left when the last hand was played, a cleared level pays them out
+	Cleared      bool   // every rock is gone, OnLevelCleared has fired
+	Index        int    // which of the run's levels this is, 0 is the first
 
 	// State machine fields
 	scoringState          ScoringState // The current state of the scoring animation
@@ -37,12 +42,21 @@ type Level struct {
 	finalScoringHookCount uint8
 	finalScoringArmed     bool
 
-	Hand      dice.HandRank // current hand for the level
-	ScoreHand dice.HandRank // current hand that will apply mult to the score
+	Hand      dice.HandRank    // current hand for the level
+	ScoreHand dice.HandRank    // current hand that will apply mult to the score
+	Hands     *dice.HandTable  // the run's hand mults, levels and plays
+	Events    *events.Pipeline // the run's event pipeline, every score goes through it
+	Gold      *gold.Ledger     // the run's gold, every scored hand pays into it
+
+	Result     dice.ScoreResult   // breakdown of the hand being scored. what the animation and HUD show
+	History    []dice.ScoreResult // every hand scored this level, the run log
+	resultDice []*Die             // the dice in Result, same order as Result.Dice
+	eventDice  []*dice.Die        // reused buffer for the dice of an event
 }
 
 type scoringMove struct {
 	die    *Die
+	score  dice.DieScore // what the die adds when it lands
 	frame  int
 	frames int
 	startX float32
@@ -59,19 +73,105 @@ type LevelOptions struct {
 	Rocks int // number of rocks to start on this level
 	Hands int // number of hands that can be scored (level specific, player)
 	Rolls int // number of rolls that can be made a hand (level specific, player)
+	Index int // which of the run's levels this is
+
+	Name  string      // name of the cave layer the level digs
+	Color render.Vec3 // base color of the level's rocks
+	Rules []CaveRule  // the layer's rules, subscribed by the Run while the level is played
+
+	HandTable *dice.HandTable  // owned by the run, shared between its levels. nil makes a new one
+	Events    *events.Pipeline // owned by the run, its jewelry should already be subscribed. nil makes a new one
+	Gold      *gold.Ledger     // owned by the run. nil makes a new one
 }
 
 func NewLevel(ops LevelOptions) *Level {
+	if ops.HandTable == nil {
+		ops.HandTable = dice.NewHandTable()
+	}
+	if ops.Events == nil {
+		ops.Events = events.NewPipeline()
+	}
+	if ops.Gold == nil {
+		ops.Gold = &gold.Ledger{}
+	}
+
 	return &Level{
 		Rocks:        ops.Rocks,
 		MaxHands:     ops.Hands,
 		HandsLeft:    ops.Hands,
 		MaxRolls:     ops.Rolls,
 		RollsLeft:    ops.Rolls,
+		Hands:        ops.HandTable,
+		Events:       ops.Events,
+		Gold:         ops.Gold,
+		Index:        ops.Index,
 		scoringState: SCORING_IDLE, // default
 	}
 }
 
+// spends one of the level's hands, false when there are none left.
+// RollsLeft goes back to MaxRolls for the next hand, the rolls that weren't used are kept in RollsUnused
+func (l *Level) PlayHand() bool {
+	if l.HandsLeft <= 0 {
+		return false
+	}
+	l.HandsLeft--
+	l.RollsUnused = l.RollsLeft
+	l.RollsLeft = l.MaxRolls
+	return true
+}
+
+// locks in hand as the ScoreHand and breaks down the score of the dice before any of them land
+func (l *Level) BeginScoring(hand dice.HandRank, scoringDice []*Die) {
+	l.ScoreHand = hand
+	l.resultDice = append(l.resultDice[:0], scoringDice...)
+
+	result, ctx := l.Events.ScoreHand(l.diceOf(scoringDice), hand, l.Hands)
+	l.Result = result
+	l.applyEvent(ctx)
+}
+
+func (l *Level) diceOf(from []*Die) []*dice.Die {
+	l.eventDice = l.eventDice[:0]
+	for _, die := range from {
+		l.eventDice = append(l.eventDice, &die.Die)
+	}
+	return l.eventDice
+}
+
+// fires event through Events for the dice it is about, OnRoll with the dice that rolled etc.
+func (l *Level) Fire(event events.Event, about ...*Die) {
+	ctx := &events.Context{Event: event, Hand: l.Hand, Dice: l.diceOf(about), Level: l.Index}
+	l.Events.Fire(ctx)
+	l.applyEvent(ctx)
+}
+
+// applies the rolls and rocks subscribers added
+func (l *Level) applyEvent(ctx *events.Context) {
+	l.RollsLeft = max(l.RollsLeft+ctx.Rolls, 0)
+	l.Rocks += ctx.Rocks
+	l.checkCleared()
+}
+
+// fires OnLevelCleared the first time every rock is gone
+func (l *Level) checkCleared() {
+	if l.Cleared || l.Rocks > 0 {
+		return
+	}
+	l.Cleared = true
+	l.Fire(events.OnLevelCleared)
+}
+
+// returns the part of Result that belongs to die
+func (l *Level) dieScore(die *Die) dice.DieScore {
+	for i, resultDie := range l.resultDice {
+		if resultDie == die {
+			return l.Result.Dice[i]
+		}
+	}
+	return dice.DieScore{}
+}
+
 // handles scoring and render changes, used in g.ActiveLevel
 //
 // TODO: make this animation better/more fun
@@ -84,7 +184,7 @@ func (l *Level) HandleScoring(heldDice []*Die, rockRenderer *rocks.RocksRenderer
 	// when a die that just became HELD it's x/y is determined on it's position from
 	// where the cursor was, essentially 'slotting' it between the other dice
 	sort.Slice(heldDice, func(i, j int) bool {
-		return heldDice[i].ActiveFace().NumPips() < heldDice[j].ActiveFace().NumPips()
+		return heldDice[i].ActiveFace().Value() < heldDice[j].ActiveFace().Value()
 	})
 
 	if l.scoringState == SCORING_IDLE {
@@ -143,6 +243,7 @@ func (l *Level) startScoringDie(heldDice []*Die, musicState *music.NowPlaying) {
 
 	l.scoringMoves = append(l.scoringMoves, scoringMove{
 		die:    die,
+		score:  l.dieScore(die),
 		frames: frames,
 		startX: die.Vec2.X,
 		startY: die.Vec2.Y,
@@ -292,14 +393,18 @@ func (l *Level) landScoringDie(move *scoringMove, rockRenderer *rocks.RocksRende
 	die.Vec2.Y = move.endY
 	die.Velocity.X = 0
 	die.Velocity.Y = 0
-	l.CurrentScore += die.ActiveFace().Value()
+	l.CurrentScore += move.score.Score
 	rockRenderer.ExplodeRocks(die.Identifier, die.ActiveFace().NumPips())
 	move.landed = true
 }
 
 func (l *Level) finishScoring(heldDice []*Die, rockRenderer *rocks.RocksRenderer) {
-	l.CurrentScore = int(float32(l.CurrentScore) * l.ScoreHand.Multiplier())
+	l.CurrentScore = l.Result.Total
+	l.Hands.Played(l.ScoreHand)
+	l.History = append(l.History, l.Result)
+	l.Gold.Score(l.Result, l.Result.MultSource)
 	l.Rocks -= l.CurrentScore
+	l.checkCleared()
 
 	sumOfNumPips := 0
 	for _, d := range heldDice {
@@ -352,13 +457,13 @@ func (l *Level) finishScoring(heldDice []*Die, rockRenderer *rocks.RocksRenderer
 
 func (l Level) String() string {
 	if l.ScoreHand != dice.NO_HAND {
-		return fmt.Sprintf("%-2d/%2d hands | %-2d/%2d rolls | %-4d rocks | %s %.2fx | %d",
+		return fmt.Sprintf("%-2d/%2d hands | %-2d/%2d rolls | %-4d rocks | %s %s | %d",
 			l.HandsLeft, l.MaxHands, l.RollsLeft, l.MaxRolls, l.Rocks,
-			l.ScoreHand.String(), l.ScoreHand.Multiplier(), l.CurrentScore)
+			l.Result.MultSource, dice.MultString(l.Result.Mult), l.CurrentScore)
 
 	}
 
-	return fmt.Sprintf("%-2d/%2d hands | %-2d/%2d rolls | %-4d rocks | %s %.2fx | %d",
+	return fmt.Sprintf("%-2d/%2d hands | %-2d/%2d rolls | %-4d rocks | %s | %d",
 		l.HandsLeft, l.MaxHands, l.RollsLeft, l.MaxRolls, l.Rocks,
-		l.Hand.String(), l.Hand.Multiplier(), l.CurrentScore)
+		l.Hands.Label(l.Hand), l.CurrentScore)
 }
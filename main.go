@@ -16,9 +16,12 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
+	"github.com/ninesl/dice-will-roll/gems"
 	"github.com/ninesl/dice-will-roll/music"
 	"github.com/ninesl/dice-will-roll/render"
 	"github.com/ninesl/dice-will-roll/render/shaders"
+	"github.com/ninesl/dice-will-roll/rng"
 	"github.com/ninesl/dice-will-roll/rocks"
 )
 
@@ -39,7 +42,10 @@ var (
 
 // Command-line flags
 var (
-	numRocks = flag.Int("rocks", 10000, "Number of rocks to generate")
+	numRocks = flag.Int("rocks", 0, "Number of rocks on the cave's first layer, the deeper layers scale with it. 0 uses the cave's own counts")
+	caveName = flag.String("cave", "", "Cave of the Mine to dig (Shist, Slate, Gneiss, Marble). empty uses the save's cave or Shist")
+	runSeed  = flag.Uint64("seed", 0, "Seed for the run's dice rolls, die spawns and rock fields. 0 picks a random seed")
+	savePath = flag.String("save", "", "Run save file. the run continues from it when it exists and is written when the game closes")
 )
 
 func init() {
@@ -82,15 +88,20 @@ type Game struct {
 	RocksRenderer *rocks.RocksRenderer // New rocks rendering system,
 	opts          *DrawOptions
 
-	ActiveLevel *Level // keeping track of rocks
+	Run         *Run   // the levels of the run, moves ActiveLevel along
+	ActiveLevel *Level // keeping track of rocks, always Run.Level
 	Music       *music.NowPlaying
+	RNG         *rng.Streams       // every gameplay random number for the run comes from here
+	Hands       *dice.HandTable    // the run's hand mults. upgrades only last for this run
+	Jewelry     *gems.JewelrySlots // the run's equipped jewelry
+	Events      *events.Pipeline   // what gems, jewelry and level rules subscribe to
 
 	// //TODO:FIXME: make a new one per level?, game renders the same but active level reassigns
 	Dice               []*Die        // Player's dice
 	diceCenterBuffer   []render.Vec3 // Pre-allocated die center buffer (X=centerX, Y=centerY, Z=360rotation axis)
 	diceVelocityBuffer []render.Vec2 // Pre-allocated die velocity buffer (X=velocityX, Y=velocityY)
 	heldDie            []*Die        // Reused scratch buffer of currently held dice.
-	hold               []dice.Die    // Reused scratch buffer for hand ranking held dice.
+	rolled             []*Die        // Reused scratch buffer of the dice a roll rolled.
 
 	Mouse MouseInfo
 
@@ -98,6 +109,7 @@ type Game struct {
 	holdTime       time.Time
 	activeDieIdx   int // active die index, g.ActiveDie() to get the *Die
 	holdCx, holdCy float32
+	pressedHeld    bool // the pressed die was in the hand, so letting it go elsewhere releases it
 	// is updated with UpdateCursor() in update loop
 	//cx, cy    float32 // the x/y coordinates of the cursor
 	// holdCx, holdCy float32
@@ -138,35 +150,55 @@ func LoadGame() *Game {
 	}
 	nowPlaying.Play()
 
-	playerDice := SetupPlayerDice()
+	seed := *runSeed
+	hands := dice.NewHandTable()
+	selected := *caveName
+	if *savePath != "" {
+		save, ok, err := LoadRunSave(*savePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if ok {
+			if seed == 0 {
+				seed = save.Seed
+			}
+			hands = save.Hands
+			if selected == "" {
+				selected = save.Cave
+			}
+		}
+	}
+	if seed == 0 {
+		seed = rng.RandomSeed()
+	}
+	streams := rng.New(seed)
 
-	rockAmount := *numRocks
-	// Initialize rocks renderer with hybrid real-time 3D SDF system
-	rocksConfig := rocks.RocksConfig{
-		TotalRocks: []int{rockAmount},
-		BaseColors: []render.Vec3{
-			render.Grey,
-			// render.Brown,
-			// render.RainbowColors[0],
-			// render.RainbowColors[1],
-			// render.RainbowColors[2],
-			// render.RainbowColors[3],
-			// render.RainbowColors[4],
-			// render.RainbowColors[5],
-			// render.RainbowColors[6],
-		},
-		RockTileSize:          rocks.CalculateRockTileSize(TileSize, rockAmount), // Dynamically scaled based on rock amount
-		WorldBoundsX:          float32(render.GAME_BOUNDS_X),
-		WorldBoundsY:          float32(render.GAME_BOUNDS_Y),
-		ColorTransitionFrames: 30, // 30 frames (~0.5 seconds at 60fps)
+	jewelry := &gems.JewelrySlots{}
+	pipeline := events.NewPipeline()
+	jewelry.Subscribe(pipeline)
+
+	playerDice := SetupPlayerDice(streams)
+
+	// the cave is picked before the run starts, from --cave or the save. there is no selection screen yet
+	if selected == "" {
+		selected = CAVES[0].Name
+	}
+	cave, err := SelectCave(selected)
+	if err != nil {
+		log.Fatal(err)
 	}
+	run := NewRun(cave, *numRocks, hands, jewelry, pipeline)
 
 	g := &Game{
 		UIState:       NewUIState(),
 		Dice:          playerDice,
 		Shaders:       shaders.LoadShaders(),
-		RocksRenderer: rocks.NewRocksRenderer(rocksConfig),
+		RocksRenderer: rocks.NewRocksRenderer(newRocksConfig(run.Levels[run.Index:], streams)),
 		Music:         nowPlaying,
+		RNG:           streams,
+		Hands:         hands,
+		Jewelry:       jewelry,
+		Events:        pipeline,
 		opts: &DrawOptions{
 			image:  &ebiten.DrawImageOptions{},
 			text:   &text.DrawOptions{},
@@ -174,13 +206,9 @@ func LoadGame() *Game {
 		diceCenterBuffer:   make([]render.Vec3, 0, NUM_PLAYER_DICE),
 		diceVelocityBuffer: make([]render.Vec2, 0, NUM_PLAYER_DICE),
 		heldDie:            make([]*Die, 0),
-		hold:               make([]dice.Die, 0),
 		startTime:          time.Now(),
-		ActiveLevel: NewLevel(LevelOptions{
-			Rocks: rockAmount,
-			Hands: 10,
-			Rolls: 2,
-		}),
+		Run:                run,
+		ActiveLevel:        run.Level,
 	}
 
 	var rocksImage *ebiten.Image = ebiten.NewImage(g.Bounds())
@@ -191,6 +219,27 @@ func LoadGame() *Game {
 	return g
 }
 
+// the rock field for the first of levels, the deeper layers follow it in TotalRocks and BaseColors
+func newRocksConfig(levels []LevelOptions, streams *rng.Streams) rocks.RocksConfig {
+	totalRocks := make([]int, len(levels))
+	baseColors := make([]render.Vec3, len(levels))
+	for i, level := range levels {
+		totalRocks[i] = level.Rocks
+		baseColors[i] = level.Color
+	}
+
+	// Initialize rocks renderer with hybrid real-time 3D SDF system
+	return rocks.RocksConfig{
+		TotalRocks:            totalRocks,
+		BaseColors:            baseColors,
+		RockTileSize:          rocks.CalculateRockTileSize(TileSize, totalRocks[0]), // Dynamically scaled based on rock amount
+		WorldBoundsX:          float32(render.GAME_BOUNDS_X),
+		WorldBoundsY:          float32(render.GAME_BOUNDS_Y),
+		ColorTransitionFrames: 30, // 30 frames (~0.5 seconds at 60fps)
+		Rand:                  streams.Rocks,
+	}
+}
+
 func loadGameMusic() (*music.NowPlaying, error) {
 	trackFile, err := music.TracksFS.Open("tracks/json/track_iommiwatts.json")
 	if err != nil {
@@ -258,6 +307,11 @@ func main() {
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err)
 	}
+	if *savePath != "" {
+		if err := game.RunSave().Write(*savePath); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
 
 func (a Action) String() string {
@@ -0,0 +1,50 @@
+package rng
+
+import (
+	"math/rand/v2"
+)
+
+// which stream of a run a generator belongs to. used as the second PCG seed
+// so every stream made from the same run seed is independent
+const (
+	diceStream uint64 = iota + 1
+	spawnStream
+	rocksStream
+	shopStream
+	gemsStream
+)
+
+// Streams splits one run seed into a generator for each kind of gameplay randomness.
+//
+// every stream comes from the same Seed, but pulling from one (spawning an extra die)
+// never shifts the others (die faces, rock fields). Same Seed, same run
+type Streams struct {
+	Seed uint64
+
+	Dice  *rand.Rand // which face a die lands on
+	Spawn *rand.Rand // where a new die shows up
+	Rocks *rand.Rand // rock field generation
+	Shop  *rand.Rand // Rolland's offers and rerolls
+	Gems  *rand.Rand // which socketed garnets break when they are scored
+}
+
+// New makes every stream for a run from seed
+func New(seed uint64) *Streams {
+	return &Streams{
+		Seed:  seed,
+		Dice:  rand.New(rand.NewPCG(seed, diceStream)),
+		Spawn: rand.New(rand.NewPCG(seed, spawnStream)),
+		Rocks: rand.New(rand.NewPCG(seed, rocksStream)),
+		Shop:  rand.New(rand.NewPCG(seed, shopStream)),
+		Gems:  rand.New(rand.NewPCG(seed, gemsStream)),
+	}
+}
+
+// RandomSeed is used when the player doesn't pick a seed. never returns 0
+func RandomSeed() uint64 {
+	for {
+		if seed := rand.Uint64(); seed != 0 {
+			return seed
+		}
+	}
+}
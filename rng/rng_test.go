@@ -0,0 +1,51 @@
+package rng
+
+import "testing"
+
+func TestSameSeedSameStreams(t *testing.T) {
+	a, b := New(42), New(42)
+	for range 100 {
+		if a.Dice.IntN(6) != b.Dice.IntN(6) {
+			t.Fatal("Dice streams with the same seed diverged")
+		}
+		if a.Spawn.IntN(6) != b.Spawn.IntN(6) {
+			t.Fatal("Spawn streams with the same seed diverged")
+		}
+		if a.Rocks.IntN(6) != b.Rocks.IntN(6) {
+			t.Fatal("Rocks streams with the same seed diverged")
+		}
+		if a.Shop.IntN(6) != b.Shop.IntN(6) {
+			t.Fatal("Shop streams with the same seed diverged")
+		}
+		if a.Gems.IntN(6) != b.Gems.IntN(6) {
+			t.Fatal("Gems streams with the same seed diverged")
+		}
+	}
+}
+
+// pulling from one stream must not change what the others give
+func TestStreamsAreIndependent(t *testing.T) {
+	a, b := New(7), New(7)
+	for range 50 {
+		b.Spawn.IntN(6)
+		b.Rocks.Float32()
+		b.Shop.IntN(6)
+		b.Gems.IntN(6)
+	}
+
+	for range 100 {
+		if a.Dice.IntN(6) != b.Dice.IntN(6) {
+			t.Fatal("Dice stream changed after pulling from Spawn, Rocks, Shop and Gems")
+		}
+	}
+}
+
+func TestDifferentSeedsDiffer(t *testing.T) {
+	a, b := New(1), New(2)
+	for range 100 {
+		if a.Dice.Uint64() != b.Dice.Uint64() {
+			return
+		}
+	}
+	t.Fatal("seeds 1 and 2 gave the same Dice stream")
+}
@@ -17,6 +17,11 @@ func (r *RocksRenderer) updateAllBufferTransitions() {
 		}
 	}
 
+	// a promoted layer fades in from the last layer's color
+	if base := &r.BaseColorBuffers[r.ActiveBaseBufferIdx]; base.Transition > 0 {
+		base.Transition--
+	}
+
 	// Update TransitionBuffers transitions and move completed ones to the active base buffer
 	for i := len(r.TransitionBuffers) - 1; i >= 0; i-- {
 		// Decrement transition counter (stop at 0)
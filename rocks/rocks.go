@@ -3,7 +3,7 @@ package rocks
 import (
 	"fmt"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/ninesl/dice-will-roll/render"
@@ -234,6 +234,7 @@ type RocksRenderer struct {
 
 	totalRocks []int          // rock depth nums 0 is activeBaseBuffer
 	Rocks      [][]SimpleRock //technically max is uint16 65535
+	layerScore int            // score left in the active layer, in score units like TotalRocks, not a count of rocks. 0 promotes the next layer
 
 	// Three-tier buffer system for rock color management
 	BaseColorBuffers    []RockBuffer // Source rocks (Grey, Brown, etc.)
@@ -252,6 +253,9 @@ type RocksRenderer struct {
 
 	RockTileSize float32 // Base tile size for rock rendering and collision calculations
 
+	rand       *rand.Rand   // every rock's type, position and slope comes from here, see RocksConfig.Rand
+	layerRands []*rand.Rand // one stream per deeper layer, seeded from rand before play so promoting a layer doesn't depend on how the last one was played
+
 	// Internal collision buffers - reused each frame to avoid allocations
 	diceCollisionBuffer           []RockID
 	cursorCollisionBuffer         []RockID
@@ -280,12 +284,13 @@ type pendingExplosionBatch struct {
 
 // RocksConfig holds configuration for rock system
 type RocksConfig struct {
-	TotalRocks            []int         // 0-x index of depth. 0 is first base layer that is active
+	TotalRocks            []int         // 0-x index of depth. 0 is first base layer that is active, the next is promoted when it runs out
 	BaseColors            []render.Vec3 // the colors that rock render applies
 	RockTileSize          float32       // Base tile size for rock rendering and collision calculations
 	WorldBoundsX          float32
 	WorldBoundsY          float32
-	ColorTransitionFrames int // frames for color transitions (default: 30)
+	ColorTransitionFrames int        // frames for color transitions (default: 30)
+	Rand                  *rand.Rand // the run's rock stream (rng.Streams.Rocks). a random source is used when nil
 }
 
 // helper func for active buffers, wrapper allows us to stack trace and reuse in the future
@@ -318,9 +323,14 @@ func NewRocksRenderer(config RocksConfig) *RocksRenderer {
 
 		diceCollisionDataBuffer: make([]dieCollisionData, 0, 7), //TODO:constant
 		config:                  config,
+		rand:                    config.Rand,
 		// Define colors for each rock type (applied via shader at draw time)
 	}
 
+	if r.rand == nil {
+		r.rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
 	// Initialize rock size lookup table (pre-compute all size calculations)
 	r.initRockSizeLookup()
 
@@ -416,9 +426,6 @@ func (r *RocksRenderer) generateSprites() {
 
 // generateRocks creates rock instances with random RockScoreTypes that accumulate to target score
 func (r *RocksRenderer) generateRocks(config RocksConfig) {
-	// r.ActiveBaseBufferIdx is 0, only have implementation for 1
-	r.Rocks = append(r.Rocks, make([]SimpleRock, 0, config.TotalRocks[r.ActiveBaseBufferIdx]))
-
 	// Initialize empty rock buffers slice (will grow dynamically)
 	r.BaseColorBuffers = make([]RockBuffer, 0, len(config.BaseColors))
 	r.HeldColorBuffers = make(map[render.DieIdentity]RockBuffer)
@@ -427,6 +434,21 @@ func (r *RocksRenderer) generateRocks(config RocksConfig) {
 	r.selectionOrder = make([]render.DieIdentity, 0, 7) // Track selection order for draw order
 	r.updatingBuffers = make([]RockBuffer, 0)
 
+	// the deeper layers are generated when they are promoted, see PromoteLayer. their streams are split off now
+	// so the same seed digs the same layers however the rocks above them were split
+	r.generateLayer(config, r.ActiveBaseBufferIdx, r.rand)
+	r.layerRands = make([]*rand.Rand, len(config.TotalRocks))
+	for layer := r.ActiveBaseBufferIdx + 1; layer < len(config.TotalRocks); layer++ {
+		r.layerRands[layer] = rand.New(rand.NewPCG(r.rand.Uint64(), r.rand.Uint64()))
+	}
+}
+
+// generateLayer fills r.Rocks[layer] and BaseColorBuffers[layer] with a fresh rock set worth TotalRocks[layer], drawn from rnd.
+// layers are generated in order, RockIDs of a layer start at 0
+func (r *RocksRenderer) generateLayer(config RocksConfig, layer int, rnd *rand.Rand) {
+	r.Rocks = append(r.Rocks, make([]SimpleRock, 0, config.TotalRocks[layer]))
+	r.layerScore = config.TotalRocks[layer]
+
 	// targetScore := config.TotalRocks // e.g., 500
 	//
 	// // Track rocks by type
@@ -444,17 +466,17 @@ func (r *RocksRenderer) generateRocks(config RocksConfig) {
 	// 	// Generate rocks until we reach target score
 
 	baseColor := render.Vec3{}
-	if len(config.BaseColors) > r.ActiveBaseBufferIdx {
-		baseColor = config.BaseColors[r.ActiveBaseBufferIdx]
+	if len(config.BaseColors) > layer {
+		baseColor = config.BaseColors[layer]
 	}
 
 	r.BaseColorBuffers = append(r.BaseColorBuffers, RockBuffer{
-		RockIDs:         make([]RockID, 0, config.TotalRocks[r.ActiveBaseBufferIdx]),
+		RockIDs:         make([]RockID, 0, config.TotalRocks[layer]),
 		Color:           baseColor,
 		TransitionColor: baseColor,
 		Transition:      0,
 	})
-	remaining := config.TotalRocks[r.ActiveBaseBufferIdx]
+	remaining := config.TotalRocks[layer]
 	var rockIDx RockID = 0
 
 	for remaining > 0 {
@@ -465,32 +487,32 @@ func (r *RocksRenderer) generateRocks(config RocksConfig) {
 		// could be based on rock config?
 		var scoreType RockScoreType
 		switch {
-		case remaining >= HugeScore && rand.Float32() < 0.15: // 15% chance for Huge
+		case remaining >= HugeScore && rnd.Float32() < 0.15: // 15% chance for Huge
 			// Pick random Huge variant (10, 11, or 12)
-			scoreType = HugeLarge + RockScoreType(rand.Intn(rockScoreVariants))
-		case remaining >= BigScore && rand.Float32() < 0.25: // 25% chance for Big
+			scoreType = HugeLarge + RockScoreType(rnd.IntN(rockScoreVariants))
+		case remaining >= BigScore && rnd.Float32() < 0.25: // 25% chance for Big
 			// Pick random Big variant (7, 8, or 9)
-			scoreType = BigLarge + RockScoreType(rand.Intn(rockScoreVariants))
-		case remaining >= MediumScore && rand.Float32() < 0.35: // 35% chance for Medium
+			scoreType = BigLarge + RockScoreType(rnd.IntN(rockScoreVariants))
+		case remaining >= MediumScore && rnd.Float32() < 0.35: // 35% chance for Medium
 			// Pick random Medium variant (4, 5, or 6)
-			scoreType = MediumLarge + RockScoreType(rand.Intn(rockScoreVariants))
+			scoreType = MediumLarge + RockScoreType(rnd.IntN(rockScoreVariants))
 		default: // Otherwise Small
 			// Pick random Small variant (1, 2, or 3)
-			scoreType = SmallLarge + RockScoreType(rand.Intn(rockScoreVariants))
+			scoreType = SmallLarge + RockScoreType(rnd.IntN(rockScoreVariants))
 		}
 
 		// Random position
 		pos := render.Vec2{
-			X: rand.Float32() * config.WorldBoundsX,
-			Y: rand.Float32() * config.WorldBoundsY,
+			X: rnd.Float32() * config.WorldBoundsX,
+			Y: rnd.Float32() * config.WorldBoundsY,
 		}
 
 		// Pick random rotation frame
-		spriteIndex := uint8(rand.Intn(ROTATION_FRAMES))
+		spriteIndex := uint8(rnd.IntN(ROTATION_FRAMES))
 
 		// Generate slope values
-		slopeX := int8(rand.Intn(int(DIRECTIONS_TO_SNAP)+1)) - MAX_SLOPE
-		slopeY := int8(rand.Intn(int(DIRECTIONS_TO_SNAP)+1)) - MAX_SLOPE
+		slopeX := int8(rnd.IntN(int(DIRECTIONS_TO_SNAP)+1)) - MAX_SLOPE
+		slopeY := int8(rnd.IntN(int(DIRECTIONS_TO_SNAP)+1)) - MAX_SLOPE
 
 		// Convert slopes to sprite indices
 		spriteSlopeX := slopeX + MAX_SLOPE
@@ -513,8 +535,8 @@ func (r *RocksRenderer) generateRocks(config RocksConfig) {
 			SpriteSlopeY: spriteSlopeY,
 			Score:        scoreType,
 		}
-		r.Rocks[r.ActiveBaseBufferIdx] = append(r.Rocks[r.ActiveBaseBufferIdx], rock)
-		r.BaseColorBuffers[r.ActiveBaseBufferIdx].RockIDs = append(r.BaseColorBuffers[r.ActiveBaseBufferIdx].RockIDs, rockIDx)
+		r.Rocks[layer] = append(r.Rocks[layer], rock)
+		r.BaseColorBuffers[layer].RockIDs = append(r.BaseColorBuffers[layer].RockIDs, rockIDx)
 		rockIDx++
 		remaining -= scoreType.GetScore()
 	}
@@ -577,7 +599,7 @@ func (r *RocksRenderer) DrawRocks(screen *ebiten.Image) {
 			tempImg.DrawImage(frameImage, opts)
 		}
 
-		// Apply color shader (base buffers should have Transition = 0, so just use base color)
+		// Apply color shader (base buffers only transition when their layer was just promoted)
 		r.drawBufferWithColorShader(buffer, tempImg, screen)
 	}
 	// }
@@ -604,6 +626,72 @@ func (r *RocksRenderer) DrawRocks(screen *ebiten.Image) {
 	r.DrawExplosions(screen)
 }
 
+// Layer is the index of the active layer in RocksConfig.TotalRocks
+func (r *RocksRenderer) Layer() int {
+	return r.ActiveBaseBufferIdx
+}
+
+// HasNextLayer reports if there is a deeper layer left to promote
+func (r *RocksRenderer) HasNextLayer() bool {
+	return r.ActiveBaseBufferIdx+1 < len(r.totalRocks)
+}
+
+// LayerScore is the score left in the active layer
+func (r *RocksRenderer) LayerScore() int {
+	return r.layerScore
+}
+
+// PromoteLayer makes the next depth the active layer: a fresh rock set that fades in from the
+// last layer's base color to its own. Returns false when there is no deeper layer.
+//
+// rocks of the last layer that are still held, transitioning, exploding or waiting to explode are
+// carried into the new layer and their buffers get the new RockIDs, so every RockID stays valid.
+// base rocks left in the last layer are dropped
+func (r *RocksRenderer) PromoteLayer() bool {
+	if !r.HasNextLayer() {
+		return false
+	}
+
+	last := r.ActiveBaseBufferIdx
+	next := last + 1
+	lastColor := r.BaseColorBuffers[last].Color
+
+	r.generateLayer(r.config, next, r.layerRands[next])
+	r.carryRocks(last, next)
+	r.AssignActiveBuffer(next)
+
+	base := &r.BaseColorBuffers[next]
+	base.TransitionColor = lastColor
+	base.Transition = r.config.ColorTransitionFrames
+	return true
+}
+
+// carryRocks moves every rock of layer from that is still in a buffer to the end of layer to, rewriting the RockIDs in place
+func (r *RocksRenderer) carryRocks(from, to int) {
+	carry := func(rockIDs []RockID) {
+		for i, rockID := range rockIDs {
+			r.Rocks[to] = append(r.Rocks[to], r.Rocks[from][rockID])
+			rockIDs[i] = RockID(len(r.Rocks[to]) - 1)
+		}
+	}
+
+	for _, buffer := range r.HeldColorBuffers {
+		carry(buffer.RockIDs)
+	}
+	for _, buffer := range r.TransitionBuffers {
+		carry(buffer.RockIDs)
+	}
+	for _, buffer := range r.ExplosionBuffers {
+		carry(buffer.RockIDs)
+	}
+	for _, batch := range r.pendingExplosionBatches[r.pendingExplosionBatchIdx:] {
+		carry(batch.rockIDs)
+	}
+
+	r.BaseColorBuffers[from].RockIDs = r.BaseColorBuffers[from].RockIDs[:0]
+	r.Rocks[from] = nil
+}
+
 // SplitRock converts one exploding rock into smaller child rocks.
 // The children start centered on the parent, then bounce outward in a radial pattern.
 // Rocks that are already SmallScore do not split further and return a nil slice
@@ -623,7 +711,7 @@ func (r *RocksRenderer) SplitRock(rockID RockID) []RockID {
 
 	children := make([]SimpleRock, 0, len(childScores))
 	for i, score := range childScores {
-		childType := randomRockTypeForScore(score)
+		childType := r.randomRockTypeForScore(score)
 		childSize := rockSizeLookup[childType]
 		child := SimpleRock{
 			Position: render.Vec2{
@@ -667,16 +755,16 @@ func splitRockScores(score int) []int {
 
 // randomRockTypeForScore chooses a visual size variant for a logical score value.
 // The score controls gameplay value, while the returned RockScoreType adds size variety.
-func randomRockTypeForScore(score int) RockScoreType {
+func (r *RocksRenderer) randomRockTypeForScore(score int) RockScoreType {
 	switch score {
 	case SmallScore:
-		return SmallLarge + RockScoreType(rand.Intn(rockScoreVariants))
+		return SmallLarge + RockScoreType(r.rand.IntN(rockScoreVariants))
 	case MediumScore:
-		return MediumLarge + RockScoreType(rand.Intn(rockScoreVariants))
+		return MediumLarge + RockScoreType(r.rand.IntN(rockScoreVariants))
 	case BigScore:
-		return BigLarge + RockScoreType(rand.Intn(rockScoreVariants))
+		return BigLarge + RockScoreType(r.rand.IntN(rockScoreVariants))
 	case HugeScore:
-		return HugeLarge + RockScoreType(rand.Intn(rockScoreVariants))
+		return HugeLarge + RockScoreType(r.rand.IntN(rockScoreVariants))
 	default:
 		panic(fmt.Errorf("invalid rock score %d", score))
 	}
@@ -684,11 +772,30 @@ func randomRockTypeForScore(score int) RockScoreType {
 
 // ExplodeRocks batches up to numRocks rocks into the initiating die's color.
 // The visual explosion starts on a later frame via UpdatePendingExplosionBatches.
+//
+// when the active layer runs out the next layer is promoted and the rest of numRocks carries into it
 func (r *RocksRenderer) ExplodeRocks(dieIdentity render.DieIdentity, numRocks int) {
 	if numRocks <= 0 {
 		return
 	}
 
+	if numRocks >= r.layerScore && r.HasNextLayer() {
+		carry := numRocks - r.layerScore
+		r.explodeRocks(dieIdentity, r.layerScore)
+		r.PromoteLayer()
+		r.ExplodeRocks(dieIdentity, carry)
+		return
+	}
+
+	r.layerScore = max(r.layerScore-numRocks, 0)
+	r.explodeRocks(dieIdentity, numRocks)
+}
+
+func (r *RocksRenderer) explodeRocks(dieIdentity render.DieIdentity, numRocks int) {
+	if numRocks <= 0 {
+		return
+	}
+
 	rockIDs := r.popRocksForExplosion(dieIdentity, numRocks)
 	if len(rockIDs) == 0 {
 		return
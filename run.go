@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
+	"github.com/ninesl/dice-will-roll/gems"
+	"github.com/ninesl/dice-will-roll/gold"
+)
+
+// RunState is where a run is between its levels
+type RunState uint8
+
+const (
+	RUN_PLAYING       RunState = iota
+	RUN_LEVEL_CLEARED          // every rock of the level is gone, waiting on Advance()
+	RUN_GAME_OVER              // hands ran out with rocks left
+	RUN_CLEARED                // the last level was cleared
+)
+
+// tunables for the levels of a run, see Cave.Levels()
+var (
+	RUN_HANDS = 10 // hands per level
+	RUN_ROLLS = 2  // rolls per hand
+)
+
+// A run is the ordered levels the player goes through, one per layer of its Cave.
+// owns the active Level and moves on to the next one when it is cleared
+type Run struct {
+	Cave   Cave
+	Levels []LevelOptions // every level of the run, in order
+	Index  int            // index of the active level in Levels
+	State  RunState
+	Level  *Level // the active level
+
+	Hands   *dice.HandTable    // shared by every level
+	Jewelry *gems.JewelrySlots // equipped for the whole run, subscribed to Events
+	Events  *events.Pipeline   // shared by every level, the run events are fired on it
+	Gold    *gold.Ledger       // the run's gold, every level pays into it
+
+	rules []events.Subscription // the active layer's rules
+}
+
+// starts the first layer of cave, see Cave.Levels() for startRocks. hands and pipeline are given to every level, jewelry should already be subscribed to pipeline
+func NewRun(cave Cave, startRocks int, hands *dice.HandTable, jewelry *gems.JewelrySlots, pipeline *events.Pipeline) *Run {
+	if hands == nil {
+		hands = dice.NewHandTable()
+	}
+	if pipeline == nil {
+		pipeline = events.NewPipeline()
+	}
+
+	r := &Run{
+		Cave:    cave,
+		Levels:  cave.Levels(startRocks),
+		Hands:   hands,
+		Jewelry: jewelry,
+		Events:  pipeline,
+		Gold:    &gold.Ledger{},
+	}
+	r.startLevel(0, 0)
+	return r
+}
+
+// carry is score left over from the last level's final hand, it breaks rocks of this one
+func (r *Run) startLevel(index, carry int) {
+	ops := r.Levels[index]
+	ops.Rocks -= carry
+	ops.Index = index
+	ops.HandTable = r.Hands
+	ops.Events = r.Events
+	ops.Gold = r.Gold
+
+	for _, sub := range r.rules {
+		r.Events.Unsubscribe(sub)
+	}
+	r.rules = r.rules[:0]
+	for _, rule := range ops.Rules {
+		r.rules = append(r.rules, r.Events.Subscribe(rule.Event, events.RULE_ORDER, rule.Name, rule.Handler))
+	}
+
+	r.Index = index
+	r.Gold.Level = index
+	r.State = RUN_PLAYING
+	r.Level = NewLevel(ops)
+	r.Level.Fire(events.OnLevelStarted)
+}
+
+// checks the active level once it is done scoring.
+// a cleared level pays out its leftover hands and the rolls its last hand didn't use and waits for Advance(), running out of hands ends the run
+func (r *Run) Update() RunState {
+	if r.State != RUN_PLAYING || r.Level.scoringState != SCORING_IDLE {
+		return r.State
+	}
+
+	if r.Level.Cleared {
+		r.Gold.LevelCleared(r.Level.HandsLeft, r.Level.RollsUnused)
+	}
+	switch {
+	case r.Level.Cleared && r.Index == len(r.Levels)-1:
+		r.State = RUN_CLEARED
+		r.Events.FireLevel(events.OnRunCleared, r.Index)
+	case r.Level.Cleared:
+		r.State = RUN_LEVEL_CLEARED
+	case r.Level.HandsLeft <= 0 && r.Level.Rocks > 0:
+		r.State = RUN_GAME_OVER
+		r.Events.FireLevel(events.OnGameOver, r.Index)
+	}
+	return r.State
+}
+
+// starts the next level after a cleared one. every die goes back to ROLLING.
+// score past the last rock carries into the next level
+func (r *Run) Advance(playerDice []*Die) {
+	if r.State != RUN_LEVEL_CLEARED {
+		return
+	}
+
+	for _, die := range playerDice {
+		die.Mode = ROLLING
+		die.Fixed.X = 0
+		die.Fixed.Y = 0
+		die.Height = 0
+	}
+	r.startLevel(r.Index+1, max(-r.Level.Rocks, 0))
+}
+
+// true when the player can't do anything anymore
+func (r *Run) Over() bool {
+	return r.State == RUN_GAME_OVER || r.State == RUN_CLEARED
+}
+
+func (r *Run) String() string {
+	switch r.State {
+	case RUN_GAME_OVER:
+		return fmt.Sprintf("GAME OVER | %s %s %d/%d | %d rocks left", r.Cave.Name, r.Levels[r.Index].Name, r.Index+1, len(r.Levels), r.Level.Rocks)
+	case RUN_CLEARED:
+		return fmt.Sprintf("%s CLEARED | %d layers | %dg", r.Cave.Name, len(r.Levels), r.Gold.Balance())
+	}
+	return fmt.Sprintf("%s %s %d/%d | %dg | %s", r.Cave.Name, r.Levels[r.Index].Name, r.Index+1, len(r.Levels), r.Gold.Balance(), r.Level.String())
+}
+
+// the gold the last cleared level paid out, itemized. empty before the first level is cleared
+func (r *Run) GoldSummary() string {
+	level := r.Index - 1
+	if r.State == RUN_CLEARED {
+		level = r.Index
+	}
+	if level < 0 {
+		return ""
+	}
+
+	lines := r.Gold.Summary(level)
+	parts := make([]string, len(lines))
+	for i, line := range lines {
+		parts[i] = line.String()
+	}
+	return fmt.Sprintf("%s gold: %s", r.Levels[level].Name, strings.Join(parts, " | "))
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ninesl/dice-will-roll/gold"
+)
+
+func TestLevelClearedPaysUnusedRolls(t *testing.T) {
+	run := NewRun(CAVES[0], 0, nil, nil, nil)
+	level := run.Level
+	level.MaxRolls = 3
+	level.RollsLeft = 1
+
+	if !level.PlayHand() {
+		t.Fatal("PlayHand() with hands left = false")
+	}
+	if level.RollsLeft != level.MaxRolls || level.RollsUnused != 1 {
+		t.Errorf("after PlayHand() RollsLeft %d RollsUnused %d; want %d, 1", level.RollsLeft, level.RollsUnused, level.MaxRolls)
+	}
+
+	level.Cleared = true
+	if state := run.Update(); state != RUN_LEVEL_CLEARED {
+		t.Fatalf("Update() = %d; want RUN_LEVEL_CLEARED", state)
+	}
+	paid := 0
+	for _, line := range run.Gold.Summary(0) {
+		if line.Reason == gold.RollsLeft {
+			paid = line.Amount
+		}
+	}
+	if paid != gold.GOLD_PER_ROLL_LEFT {
+		t.Errorf("cleared level paid %d for rolls left; want %d for the 1 unused roll", paid, gold.GOLD_PER_ROLL_LEFT)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/ninesl/dice-will-roll/dice"
+)
+
+const RUN_SAVE_VERSION = 1
+
+// RunSave is everything about a run that outlives the game window.
+// the seed replays the run's rolls, spawns and rock fields, the hand table keeps hand levels
+type RunSave struct {
+	Version int             `json:"version"`
+	Seed    uint64          `json:"seed"`
+	Cave    string          `json:"cave,omitempty"` // name of the run's cave, see SelectCave
+	Hands   *dice.HandTable `json:"hands"`
+}
+
+// reads a run save, ok is false when there is no save at path yet
+func LoadRunSave(path string) (save RunSave, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return RunSave{}, false, nil
+	}
+	if err != nil {
+		return RunSave{}, false, err
+	}
+
+	if err := json.Unmarshal(data, &save); err != nil {
+		return RunSave{}, false, fmt.Errorf("run save %s: %w", path, err)
+	}
+	if save.Version != RUN_SAVE_VERSION {
+		return RunSave{}, false, fmt.Errorf("run save %s is version %d, want %d", path, save.Version, RUN_SAVE_VERSION)
+	}
+	if save.Hands == nil {
+		save.Hands = dice.NewHandTable()
+	}
+	return save, true, nil
+}
+
+func (s RunSave) Write(path string) error {
+	s.Version = RUN_SAVE_VERSION
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// the run as it is now
+func (g *Game) RunSave() RunSave {
+	return RunSave{Seed: g.RNG.Seed, Cave: g.Run.Cave.Name, Hands: g.Hands}
+}
@@ -0,0 +1,264 @@
+package shop
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/gems"
+)
+
+// Kind is what an Item sells
+type Kind uint8
+
+const (
+	KindDie         Kind = iota // a new die for the player's bag
+	KindFace                    // a face to put on one of the player's dice
+	KindGem                     // a rough gem
+	KindJewelry                 // a piece of jewelry to equip
+	KindHandUpgrade             // levels up a hand in the run's dice.HandTable
+
+	NUM_KINDS // not a kind. used for validation and iterating
+)
+
+type kindInfo struct {
+	name  string
+	price int // gold for a Common, see Rarity.Price
+}
+
+var kindTable = [NUM_KINDS]kindInfo{
+	KindDie:         {name: "Die", price: 8},
+	KindFace:        {name: "Face", price: 4},
+	KindGem:         {name: "Gem", price: 6},
+	KindJewelry:     {name: "Jewelry", price: 10},
+	KindHandUpgrade: {name: "Hand Upgrade", price: 5},
+}
+
+func (k Kind) Valid() bool {
+	return k < NUM_KINDS
+}
+
+func (k Kind) String() string {
+	if !k.Valid() {
+		return fmt.Sprintf("Kind(%d)", uint8(k))
+	}
+	return kindTable[k].name
+}
+
+// Rarity decides how often an offer shows up and how much it costs
+type Rarity uint8
+
+const (
+	Common Rarity = iota
+	Uncommon
+	Rare
+	Legendary
+
+	NUM_RARITIES
+)
+
+type rarityInfo struct {
+	name   string
+	weight int     // chance of an offer being this rarity is weight / sum of every weight
+	price  float32 // multiplies the kind's price
+}
+
+var rarityTable = [NUM_RARITIES]rarityInfo{
+	Common:    {name: "Common", weight: 60, price: 1},
+	Uncommon:  {name: "Uncommon", weight: 25, price: 1.5},
+	Rare:      {name: "Rare", weight: 12, price: 2.5},
+	Legendary: {name: "Legendary", weight: 3, price: 4},
+}
+
+func (r Rarity) Valid() bool {
+	return r < NUM_RARITIES
+}
+
+func (r Rarity) String() string {
+	if !r.Valid() {
+		return fmt.Sprintf("Rarity(%d)", uint8(r))
+	}
+	return rarityTable[r].name
+}
+
+// what an Item of kind and this rarity costs
+func (r Rarity) Price(kind Kind) int {
+	return int(float32(kindTable[kind].price) * rarityTable[r].price)
+}
+
+// picks a rarity by weight
+func randomRarity(r *rand.Rand) Rarity {
+	total := 0
+	for _, info := range rarityTable {
+		total += info.weight
+	}
+
+	roll := r.IntN(total)
+	for rarity, info := range rarityTable {
+		if roll < info.weight {
+			return Rarity(rarity)
+		}
+		roll -= info.weight
+	}
+	return Common
+}
+
+// Item is one thing Rolland sells. only the field for its Kind is set
+type Item struct {
+	Kind   Kind
+	Rarity Rarity
+	Price  int // what it costs to buy
+
+	Die     dice.Die      // KindDie
+	Face    dice.Face     // KindFace
+	Gem     gems.Gem      // KindGem
+	Jewelry gems.Jewelry  // KindJewelry
+	Hand    dice.HandRank // KindHandUpgrade, levels up by one
+}
+
+// part of the price given back when an item is sold
+const SELL_RATE = 0.5
+
+// what Rolland pays for the item, SELL_RATE of what it costs on the shelf.
+// a gem is priced as the rough stone, cutting, tumbling and polishing it is for its socket not for Rolland
+func (i Item) SellPrice() int {
+	price := i.Price
+	if price == 0 {
+		price = i.Rarity.Price(i.Kind)
+		if i.Kind == KindGem {
+			price = gemPrice(i.Gem.Type, i.Rarity)
+		}
+	}
+	return max(int(float32(price)*SELL_RATE), 1)
+}
+
+// the shelf price of a rough gem of t
+func gemPrice(t gems.Type, rarity Rarity) int {
+	rough := gems.Gem{Stone: gems.Stone{Type: t}}
+	return int(float32(rough.Value()) * rarityTable[rarity].price)
+}
+
+// the name shown on the shelf, "Rare d10" "Uncommon Ruby" "Full House Lv.+1"
+func (i Item) Name() string {
+	var name string
+	switch i.Kind {
+	case KindDie:
+		name = fmt.Sprintf("d%d", i.Die.NumFaces())
+	case KindFace:
+		mods := ""
+		for _, mod := range i.Face.Modifiers() {
+			if mod != dice.ModNONE {
+				mods = mod.String() + " "
+				break
+			}
+		}
+		name = fmt.Sprintf("%s%d Face", mods, i.Face.NumPips())
+	case KindGem:
+		name = i.Gem.String()
+	case KindJewelry:
+		name = i.Jewelry.Name
+	case KindHandUpgrade:
+		name = i.Hand.String() + " Lv.+1"
+	default:
+		name = i.Kind.String()
+	}
+	return i.Rarity.String() + " " + name
+}
+
+// "Rare d10 20g"
+func (i Item) String() string {
+	return fmt.Sprintf("%s %dg", i.Name(), i.Price)
+}
+
+// what each rarity offers of each kind
+var (
+	rarityDieSides = [NUM_RARITIES][]int{
+		Common:    {4, 6},
+		Uncommon:  {8},
+		Rare:      {10, 12},
+		Legendary: {20},
+	}
+	rarityFaceMods = [NUM_RARITIES][]dice.Modifier{
+		Common:    {dice.ModBONUS},
+		Uncommon:  {dice.ModGOLD, dice.ModLOADED},
+		Rare:      {dice.ModMULT, dice.ModBONUS_BIG},
+		Legendary: {dice.ModDOUBLE, dice.ModWILD},
+	}
+	rarityGems = [NUM_RARITIES][]gems.Type{
+		Common:    {gems.Emerald, gems.Topaz},
+		Uncommon:  {gems.Onyx, gems.Ruby},
+		Rare:      {gems.Sapphire, gems.Amethyst},
+		Legendary: {gems.Diamond},
+	}
+	rarityHands = [NUM_RARITIES][]dice.HandRank{
+		Common:    {dice.HIGH_DIE, dice.ONE_PAIR, dice.TWO_PAIR},
+		Uncommon:  {dice.THREE_OF_A_KIND, dice.STRAIGHT_SMALL},
+		Rare:      {dice.STRAIGHT_LARGE, dice.FULL_HOUSE},
+		Legendary: {dice.FOUR_OF_A_KIND, dice.FIVE_OF_A_KIND},
+	}
+	// effects on a piece of jewelry, and how strong they are
+	rarityJewelry = [NUM_RARITIES]struct {
+		effects int
+		flat    float32
+		mult    float32
+	}{
+		Common:    {effects: 1, flat: 2, mult: 1.25},
+		Uncommon:  {effects: 1, flat: 4, mult: 1.5},
+		Rare:      {effects: 2, flat: 6, mult: 1.5},
+		Legendary: {effects: 3, flat: 10, mult: 2},
+	}
+)
+
+func pick[T any](r *rand.Rand, from []T) T {
+	return from[r.IntN(len(from))]
+}
+
+// a random item of kind and rarity, priced by Rarity.Price
+func randomItem(r *rand.Rand, kind Kind, rarity Rarity) Item {
+	item := Item{Kind: kind, Rarity: rarity, Price: rarity.Price(kind)}
+
+	switch kind {
+	case KindDie:
+		item.Die = dice.NewDie(pick(r, rarityDieSides[rarity]))
+	case KindFace:
+		mods := make([]dice.Modifier, r.IntN(dice.MAX_PIPS)+1)
+		mods[r.IntN(len(mods))] = pick(r, rarityFaceMods[rarity])
+		item.Face = dice.NewModFace(mods...)
+	case KindGem:
+		item.Gem = gems.Gem{Stone: gems.Stone{Type: pick(r, rarityGems[rarity])}}
+		item.Price = gemPrice(item.Gem.Type, rarity)
+	case KindJewelry:
+		item.Jewelry = randomJewelry(r, rarity)
+	case KindHandUpgrade:
+		item.Hand = pick(r, rarityHands[rarity])
+	}
+	return item
+}
+
+func randomJewelry(r *rand.Rand, rarity Rarity) gems.Jewelry {
+	info := rarityJewelry[rarity]
+	kind := gems.JewelryKind(r.IntN(int(gems.NUM_JEWELRY_KINDS)))
+	stone := gems.Type(r.IntN(int(gems.NUM_TYPES)))
+
+	jewelry := gems.Jewelry{
+		Name: fmt.Sprintf("%s %s", stone, kind),
+		Kind: kind,
+	}
+	for range info.effects {
+		effect := gems.Effect{Kind: gems.EffectKind(r.IntN(int(gems.NUM_EFFECT_KINDS)))}
+		switch effect.Kind {
+		case gems.FlatPerValue:
+			effect.Value, effect.Amount = r.IntN(6)+1, info.flat/2
+		case gems.MultPerValue:
+			effect.Value, effect.Amount = r.IntN(6)+1, info.mult
+		case gems.GoldPerValue:
+			effect.Value, effect.Amount = r.IntN(6)+1, 1
+		case gems.FlatIfHand:
+			effect.Hands, effect.Amount = []dice.HandRank{pick(r, rarityHands[r.IntN(int(rarity)+1)])}, info.flat*2
+		case gems.MultIfHand:
+			effect.Hands, effect.Amount = []dice.HandRank{pick(r, rarityHands[r.IntN(int(rarity)+1)])}, info.mult
+		}
+		jewelry.Effects = append(jewelry.Effects, effect)
+	}
+	return jewelry
+}
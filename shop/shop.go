@@ -0,0 +1,114 @@
+// Package shop is Rolland, where gold from the mine buys dice, faces, gems, jewelry and hand upgrades between caves.
+//
+// the shop is only logic, nothing draws it yet. the offers come from the run's rng.Streams.Shop,
+// so the same seed stocks the same shelf
+//
+//	rolland := shop.New(streams.Shop, run.Gold)
+//	item, err := rolland.Buy(0)
+//
+// every buy, sell and reroll is recorded in the run's gold.Ledger
+package shop
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/ninesl/dice-will-roll/gold"
+)
+
+// tunables for a visit to Rolland
+var (
+	OFFERS           = 5 // items on the shelf
+	REROLL_COST      = 5 // gold for the first reroll of a visit
+	REROLL_COST_STEP = 2 // every reroll of the same visit costs this much more
+)
+
+var (
+	ErrNotEnoughGold = gold.ErrNotEnoughGold
+	ErrNoOffer       = errors.New("no such offer")
+	ErrSoldOut       = errors.New("offer is sold out")
+)
+
+// Offer is an Item on the shelf
+type Offer struct {
+	Item
+	Sold bool
+}
+
+// Shop is one visit to Rolland
+type Shop struct {
+	Offers  []Offer
+	Gold    *gold.Ledger // the run's gold
+	Rerolls int          // rerolls this visit
+
+	rand *rand.Rand
+}
+
+// stocks a shelf of OFFERS items from r, paid for from ledger. nil is an empty ledger
+func New(r *rand.Rand, ledger *gold.Ledger) *Shop {
+	if r == nil {
+		r = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	if ledger == nil {
+		ledger = &gold.Ledger{}
+	}
+	s := &Shop{Gold: ledger, rand: r}
+	s.stock()
+	return s
+}
+
+// fills every slot of the shelf with a new offer
+func (s *Shop) stock() {
+	s.Offers = s.Offers[:0]
+	for range OFFERS {
+		rarity := randomRarity(s.rand)
+		kind := Kind(s.rand.IntN(int(NUM_KINDS)))
+		s.Offers = append(s.Offers, Offer{Item: randomItem(s.rand, kind, rarity)})
+	}
+}
+
+// the player's gold
+func (s *Shop) Balance() int {
+	return s.Gold.Balance()
+}
+
+// what the next Reroll costs
+func (s *Shop) RerollCost() int {
+	return REROLL_COST + REROLL_COST_STEP*s.Rerolls
+}
+
+// pays RerollCost for a whole new shelf, sold offers come back
+func (s *Shop) Reroll() error {
+	if err := s.Gold.Spend(gold.Reroll, s.RerollCost(), fmt.Sprintf("reroll %d", s.Rerolls+1)); err != nil {
+		return err
+	}
+	s.Rerolls++
+	s.stock()
+	return nil
+}
+
+// buys the offer at index, it stays on the shelf as Sold
+func (s *Shop) Buy(index int) (Item, error) {
+	if index < 0 || index >= len(s.Offers) {
+		return Item{}, fmt.Errorf("%w %d, there are %d", ErrNoOffer, index, len(s.Offers))
+	}
+	offer := &s.Offers[index]
+	if offer.Sold {
+		return Item{}, fmt.Errorf("%w (%s)", ErrSoldOut, offer.Name())
+	}
+	if err := s.Gold.Spend(gold.Bought, offer.Price, offer.Name()); err != nil {
+		return Item{}, err
+	}
+	offer.Sold = true
+	return offer.Item, nil
+}
+
+// sells item to Rolland for its SellPrice, returns the gold given.
+// the shop doesn't know what the player has, the caller takes item out of the player's
+// dice, gems or jewelry first and only sells it if it was there
+func (s *Shop) Sell(item Item) int {
+	price := item.SellPrice()
+	s.Gold.Earn(gold.Sold, price, item.Name())
+	return price
+}
@@ -0,0 +1,176 @@
+package shop
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/gems"
+	"github.com/ninesl/dice-will-roll/gold"
+	"github.com/ninesl/dice-will-roll/rng"
+)
+
+func shelf(s *Shop) []string {
+	names := make([]string, len(s.Offers))
+	for i, offer := range s.Offers {
+		names[i] = offer.String()
+	}
+	return names
+}
+
+func TestSeededOffers(t *testing.T) {
+	a, b := New(rng.New(42).Shop, nil), New(rng.New(42).Shop, nil)
+	if len(a.Offers) != OFFERS {
+		t.Fatalf("%d offers; want %d", len(a.Offers), OFFERS)
+	}
+	for i, name := range shelf(a) {
+		if shelf(b)[i] != name {
+			t.Fatalf("same seed stocked %v and %v", shelf(a), shelf(b))
+		}
+	}
+
+	for _, offer := range a.Offers {
+		if !offer.Kind.Valid() || !offer.Rarity.Valid() || offer.Price <= 0 {
+			t.Errorf("offer %v: kind %v rarity %v price %d", offer.Name(), offer.Kind, offer.Rarity, offer.Price)
+		}
+	}
+}
+
+func TestRarity(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var counts [NUM_RARITIES]int
+	for range 10000 {
+		counts[randomRarity(r)]++
+	}
+	for rarity := Common; rarity < Legendary; rarity++ {
+		if counts[rarity] <= counts[rarity+1] {
+			t.Errorf("%v came up %d times, %v %d; rarer should come up less", rarity, counts[rarity], rarity+1, counts[rarity+1])
+		}
+		for kind := range NUM_KINDS {
+			if rarity.Price(kind) >= (rarity + 1).Price(kind) {
+				t.Errorf("%v %v costs %d, %v %d; rarer should cost more", rarity, kind, rarity.Price(kind), rarity+1, (rarity + 1).Price(kind))
+			}
+		}
+	}
+}
+
+func TestRandomItems(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for kind := range NUM_KINDS {
+		for rarity := range NUM_RARITIES {
+			item := randomItem(r, kind, rarity)
+			switch kind {
+			case KindDie:
+				if item.Die.NumFaces() < 4 {
+					t.Errorf("%v has %d faces", item.Name(), item.Die.NumFaces())
+				}
+			case KindFace:
+				mods := 0
+				for _, mod := range item.Face.Modifiers() {
+					if mod != dice.ModNONE {
+						mods++
+					}
+				}
+				if mods != 1 {
+					t.Errorf("%v has %d modified pips; want 1", item.Name(), mods)
+				}
+			case KindGem:
+				if _, err := gems.NewGem(item.Gem.Type); err != nil {
+					t.Errorf("%v: %v", item.Name(), err)
+				}
+			case KindJewelry:
+				if len(item.Jewelry.Effects) != rarityJewelry[rarity].effects {
+					t.Errorf("%v has %d effects; want %d", item.Name(), len(item.Jewelry.Effects), rarityJewelry[rarity].effects)
+				}
+			case KindHandUpgrade:
+				if item.Hand == dice.NO_HAND {
+					t.Errorf("%v upgrades no hand", item.Name())
+				}
+			}
+		}
+	}
+}
+
+func TestBuyAndSell(t *testing.T) {
+	ledger := &gold.Ledger{}
+	s := New(rng.New(7).Shop, ledger)
+	s.Offers[0].Price = 10
+
+	if _, err := s.Buy(0); !errors.Is(err, ErrNotEnoughGold) {
+		t.Errorf("Buy() with no gold error = %v; want %v", err, ErrNotEnoughGold)
+	}
+	if _, err := s.Buy(OFFERS); !errors.Is(err, ErrNoOffer) {
+		t.Errorf("Buy(%d) error = %v; want %v", OFFERS, err, ErrNoOffer)
+	}
+
+	ledger.Earn(gold.Sold, 15, "gems")
+	item, err := s.Buy(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Balance() != 5 || !s.Offers[0].Sold || item.Price != 10 {
+		t.Errorf("after buying: balance %d, sold %v, price %d; want 5, true, 10", s.Balance(), s.Offers[0].Sold, item.Price)
+	}
+	if _, err := s.Buy(0); !errors.Is(err, ErrSoldOut) {
+		t.Errorf("Buy() twice error = %v; want %v", err, ErrSoldOut)
+	}
+
+	if got := s.Sell(item); got != 5 || s.Balance() != 10 {
+		t.Errorf("Sell() = %d, balance %d; want 5, 10", got, s.Balance())
+	}
+	ruby := Item{Kind: KindGem, Gem: gems.Gem{Stone: gems.Stone{Type: gems.Ruby}}}
+	rough := ruby.SellPrice()
+	if rough >= ruby.Gem.Value() {
+		t.Errorf("%v sells for %d; want less than its value %d", ruby.Name(), rough, ruby.Gem.Value())
+	}
+	ruby.Gem.Cut, ruby.Gem.Tumbled, ruby.Gem.Polished = gems.NUM_CUTS-1, true, true
+	if got := s.Sell(ruby); got != rough {
+		t.Errorf("Sell(%v) = %d; want %d, working a gem shouldn't make it worth more to Rolland", ruby.Name(), got, rough)
+	}
+
+	reasons := []gold.Reason{gold.Sold, gold.Bought, gold.Sold, gold.Sold}
+	if len(ledger.Entries) != len(reasons) {
+		t.Fatalf("ledger has %v; want %v", ledger.Entries, reasons)
+	}
+	for i, entry := range ledger.Entries {
+		if entry.Reason != reasons[i] {
+			t.Errorf("entry %d is %v; want %v", i, entry, reasons[i])
+		}
+	}
+}
+
+func TestReroll(t *testing.T) {
+	ledger := &gold.Ledger{}
+	ledger.Earn(gold.Sold, REROLL_COST*2+REROLL_COST_STEP, "gems")
+	s := New(rng.New(9).Shop, ledger)
+	s.Offers[0].Sold = true
+	before := shelf(s)
+
+	if err := s.Reroll(); err != nil {
+		t.Fatal(err)
+	}
+	if s.RerollCost() != REROLL_COST+REROLL_COST_STEP {
+		t.Errorf("second reroll costs %d; want %d", s.RerollCost(), REROLL_COST+REROLL_COST_STEP)
+	}
+	if s.Offers[0].Sold {
+		t.Error("reroll kept a sold offer")
+	}
+	same := true
+	for i, name := range shelf(s) {
+		same = same && name == before[i]
+	}
+	if same {
+		t.Errorf("reroll kept the same shelf %v", before)
+	}
+
+	if err := s.Reroll(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Balance() != 0 {
+		t.Errorf("balance after two rerolls = %d; want 0", s.Balance())
+	}
+	if err := s.Reroll(); !errors.Is(err, ErrNotEnoughGold) {
+		t.Errorf("Reroll() with no gold error = %v; want %v", err, ErrNotEnoughGold)
+	}
+}
@@ -51,10 +51,11 @@ const (
 )
 
 func DEBUGView(screen *ebiten.Image, g *Game, textOpts *text.DrawOptions, viewMode DEBUGViewMode) {
-	DEBUGDrawMessage(screen, textOpts, g.ActiveLevel.String(), 0.0)
-	DEBUGDrawMessage(screen, textOpts, fmt.Sprintf("%.2f fps / %.2f tps\n", ebiten.ActualFPS(), ebiten.ActualTPS()), FONT_SIZE)
+	DEBUGDrawMessage(screen, textOpts, g.Run.String(), 0.0)
+	DEBUGDrawMessage(screen, textOpts, fmt.Sprintf("%.2f fps / %.2f tps | seed %d\n", ebiten.ActualFPS(), ebiten.ActualTPS(), g.RNG.Seed), FONT_SIZE)
 	DEBUGMusic(screen, textOpts, g.Music)
 	DEBUGDrawMessage(screen, textOpts, "<space> to ROLL, <q> to SCORE\n", FONT_SIZE*3)
+	DEBUGDrawMessage(screen, textOpts, g.Run.GoldSummary(), FONT_SIZE*4)
 	DEBUGDiceValues(screen, textOpts, g.Dice)
 
 }
@@ -89,7 +90,7 @@ func DEBUGInitTextElements() []textElement {
 func DEBUGValuesFromDice(dice []*Die) []int {
 	var track []int
 	for i := 0; i < len(dice); i++ {
-		track = append(track, dice[i].ActiveFace().NumPips())
+		track = append(track, dice[i].ActiveFace().Value())
 	}
 	return track
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/ninesl/dice-will-roll/dice"
+	"github.com/ninesl/dice-will-roll/events"
 	"github.com/ninesl/dice-will-roll/music"
 	"github.com/ninesl/dice-will-roll/render"
 )
@@ -21,6 +22,7 @@ func (g *Game) Update() error {
 	g.RocksRenderer.UpdatePendingExplosionBatches()
 
 	g.UpdateDice()
+	g.UpdateRun()
 
 	g.PlayerInput()
 
@@ -48,27 +50,46 @@ var activeDieWiggleSpeed float32 = 0.25
 
 func (g *Game) UpdateDice() {
 	g.heldDie = g.heldDie[:0]
-	g.hold = g.hold[:0]
 
 	//DEBUGTitleFPS(g.Mouse.Position.X, g.Mouse.Position.Y)
 	for _, d := range g.Dice {
 		if d.Mode == HELD {
 			d.Height = -.5
-			g.hold = append(g.hold, d.Die)
 			g.heldDie = append(g.heldDie, d)
 		}
 	}
 
 	//closest.DieRenderable.Velocity.X
 
-	g.ActiveLevel.Hand = dice.DetermineHandRank(g.hold)
-	g.ActiveLevel.ScoringHand = FindHandRankDice(g.heldDie, g.ActiveLevel.Hand)
+	hand, indices := dice.HandIndices(g.heldDie, g.ActiveLevel.Hands)
+	g.ActiveLevel.Hand = hand
+	g.ActiveLevel.ScoringHand = g.ActiveLevel.ScoringHand[:0]
+	for _, i := range indices {
+		g.ActiveLevel.ScoringHand = append(g.ActiveLevel.ScoringHand, g.heldDie[i])
+	}
 	for _, die := range g.ActiveLevel.ScoringHand {
 		die.Height = .1
 	}
 }
 
+// moves the run on to the next level once the active one is cleared
+func (g *Game) UpdateRun() {
+	if g.Run.Update() != RUN_LEVEL_CLEARED {
+		return
+	}
+
+	g.Run.Advance(g.Dice)
+	g.ActiveLevel = g.Run.Level
+	// the renderer promotes its layers as rocks explode, catch it up when the level was cleared first
+	for g.RocksRenderer.Layer() < g.Run.Index && g.RocksRenderer.PromoteLayer() {
+	}
+}
+
 func (g *Game) PlayerInput() {
+	if g.Run.Over() {
+		return
+	}
+
 	action := g.Controls()
 	// cant make an action if scoring
 	if action != ROLLING || g.ActiveLevel.scoringState != SCORING_IDLE {
@@ -76,9 +97,14 @@ func (g *Game) PlayerInput() {
 		case ROLL:
 			if g.ActiveLevel.RollsLeft > 0 {
 				g.ActiveLevel.RollsLeft--
+				g.rolled = g.rolled[:0]
 				for _, die := range g.Dice {
+					if die.Mode == ROLLING {
+						g.rolled = append(g.rolled, die)
+					}
 					die.Roll()
 				}
+				g.ActiveLevel.Fire(events.OnRoll, g.rolled...)
 
 			} else {
 				for _, die := range g.Dice {
@@ -106,9 +132,7 @@ func (g *Game) PlayerInput() {
 		case SELECT:
 			g.Select()
 		case SCORE:
-			if g.ActiveLevel.HandsLeft > 0 {
-				g.ActiveLevel.HandsLeft--
-				g.ActiveLevel.RollsLeft = g.ActiveLevel.MaxRolls
+			if g.ActiveLevel.PlayHand() {
 				g.SetDiceToScore()
 			}
 		}